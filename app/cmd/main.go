@@ -2,28 +2,126 @@ package main
 
 import (
 	"context"
+	"flag"
+	"io/fs"
+	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
+	"app/graph"
 	"app/internal/app"
 	"app/internal/config"
+	"app/internal/persisted"
+
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func main() {
+	// Extracting queries works on client sources alone and needs no config.
+	if len(os.Args) > 1 && os.Args[1] == "extract-queries" {
+		extractQueries(os.Args[2:])
+		return
+	}
+
 	cfg := config.MustLoadConfig()
 
 	application := app.NewApp(context.Background(), cfg)
 
+	if len(os.Args) > 1 {
+		code := runCommand(application, os.Args[1])
+		shutdown(application, cfg)
+		os.Exit(code)
+	}
+
 	go func() {
 		application.HttpApp.Run()
 	}()
 
+	go application.Scheduler.Run()
+
 	// graceful stop
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	<-stop
 
-	application.HttpApp.Stop()
+	shutdown(application, cfg)
+}
+
+func shutdown(application *app.App, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownConfig.Timeout)
+	defer cancel()
+	if err := application.Shutdown(ctx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+}
+
+// runCommand executes a one-off maintenance command instead of the server
+// and returns the exit code of the process.
+func runCommand(application *app.App, name string) int {
+	switch name {
+	case "repair-comments":
+		found, err := app.ReportCrossPostReplies(context.Background(), application.RepoHolder.CommentRepo, os.Stdout)
+		if err != nil {
+			log.Printf("repair-comments: %v", err)
+			return 1
+		}
+		log.Printf("Found %d replies attached to a comment on another post", found)
+		if found > 0 {
+			return 1
+		}
+		return 0
+	default:
+		log.Printf("unknown command: %s", name)
+		return 2
+	}
+}
+
+// extractQueries writes the manifest of trusted documents for the operations
+// found in the given .graphql files and directories.
+func extractQueries(args []string) {
+	flags := flag.NewFlagSet("extract-queries", flag.ExitOnError)
+	output := flags.String("o", "persisted-queries.json", "manifest file to write")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		log.Fatal("usage: extract-queries [-o manifest.json] <file or directory>...")
+	}
+
+	var sources []*ast.Source
+	for _, root := range flags.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".graphql" {
+				return err
+			}
+			input, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, &ast.Source{Name: path, Input: string(input)})
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("extract-queries: %v", err)
+		}
+	}
+
+	schema := graph.NewExecutableSchema(graph.Config{}).Schema()
+	manifest, err := persisted.Extract(schema, sources...)
+	if err != nil {
+		log.Fatalf("extract-queries: %v", err)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Fatalf("extract-queries: %v", err)
+	}
+	defer file.Close()
+
+	if err := manifest.Write(file); err != nil {
+		log.Fatalf("extract-queries: %v", err)
+	}
+	log.Printf("Wrote %d operations from %d files to %s", len(manifest), len(sources), *output)
 }
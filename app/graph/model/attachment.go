@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+type Attachment struct {
+	ID           string    `json:"id"`
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"contentType"`
+	Size         int32     `json:"size"`
+	BlobKey      string    `json:"blobKey,omitempty"`
+	ThumbnailKey string    `json:"thumbnailKey,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -10,5 +10,15 @@ type Comment struct {
 	ParentID  *string    `json:"parentId,omitempty"`
 	Replies   []*Comment `json:"replies"`
 	Content   string     `json:"content"`
+	Edited    bool       `json:"edited"`
+	Locked    bool       `json:"locked"`
+	Depth     int32      `json:"depth"`
+	Ancestors []*Comment `json:"ancestors"`
 	CreatedAt time.Time  `json:"createdAt"`
 }
+
+type CommentEdit struct {
+	ID        string    `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
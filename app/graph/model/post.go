@@ -10,6 +10,8 @@ type Post struct {
 	Title         string     `json:"title"`
 	Content       string     `json:"content"`
 	IsCommentable bool       `json:"isCommentable"`
+	Status        PostStatus `json:"status"`
+	PublishAt     *time.Time `json:"publishAt,omitempty"`
 	Comments      []*Comment `json:"comments"`
 	CreatedAt     time.Time  `json:"createdAt"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+type PostRevision struct {
+	ID        string    `json:"id"`
+	PostID    string    `json:"postId"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type PostDiff struct {
+	Title   []*DiffLine `json:"title"`
+	Content []*DiffLine `json:"content"`
+}
+
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
@@ -1,6 +1,23 @@
 package model
 
+import (
+	"time"
+)
+
 type User struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	DisplayName *string    `json:"displayName,omitempty"`
+	Bio         *string    `json:"bio,omitempty"`
+	AvatarKey   string     `json:"avatarKey,omitempty"`
+	AvatarURL   *string    `json:"avatarUrl,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Posts       []*Post    `json:"posts"`
+	Comments    []*Comment `json:"comments"`
+	Stats       *UserStats `json:"stats"`
+}
+
+type UserStats struct {
+	PostCount    int32 `json:"postCount"`
+	CommentCount int32 `json:"commentCount"`
 }
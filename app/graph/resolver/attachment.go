@@ -0,0 +1,23 @@
+package resolver
+
+import (
+	"app/graph"
+	"app/graph/model"
+	"context"
+)
+
+func (r *attachmentResolver) URL(ctx context.Context, obj *model.Attachment) (string, error) {
+	return r.BlobStore.URL(obj.BlobKey), nil
+}
+
+func (r *attachmentResolver) ThumbnailURL(ctx context.Context, obj *model.Attachment) (*string, error) {
+	if obj.ThumbnailKey == "" {
+		return nil, nil
+	}
+	url := r.BlobStore.URL(obj.ThumbnailKey)
+	return &url, nil
+}
+
+func (r *Resolver) Attachment() graph.AttachmentResolver { return &attachmentResolver{r} }
+
+type attachmentResolver struct{ *Resolver }
@@ -5,32 +5,81 @@ import (
 	"app/graph/model"
 	"context"
 	"fmt"
-	"log"
-	"time"
 
 	"github.com/google/uuid"
 )
 
 func (r *commentResolver) Replies(ctx context.Context, obj *model.Comment, limit int32, offset int32) ([]*model.Comment, error) {
-	start := time.Now()
-	log.Printf("Fetching replies for comment %s (limit: %d, offset: %d)", obj.ID, limit, offset)
+	r.Logger.DebugContext(ctx, "Fetching comment replies", "comment_id", obj.ID, "limit", limit, "offset", offset)
 
 	parentId, err := uuid.Parse(obj.ID)
 	if err != nil {
-		log.Printf("Invalid comment ID format: %s, error: %v", obj.ID, err)
-		return nil, fmt.Errorf("invalid comment ID format")
+		return nil, NewInputError("invalid comment ID format")
 	}
 
-	replies, err := r.CommentService.GetCommentReplies(ctx, parentId, int(limit), int(offset))
+	replies, err := r.CommentService.GetCommentReplies(ctx, parentId, int(r.pageSize(limit)), int(offset))
 	if err != nil {
-		log.Printf("Error fetching replies for comment %s: %v", obj.ID, err)
 		return nil, fmt.Errorf("failed to get comment replies: %w", err)
 	}
 
-	log.Printf("Successfully fetched %d replies for comment %s in %v", len(replies), obj.ID, time.Since(start))
 	return replies, nil
 }
 
+func (r *commentResolver) Ancestors(ctx context.Context, obj *model.Comment) ([]*model.Comment, error) {
+	if obj.Depth == 0 {
+		return []*model.Comment{}, nil
+	}
+
+	commentId, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, NewInputError("invalid comment ID format")
+	}
+
+	ancestors, err := r.CommentService.GetAncestors(ctx, commentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ancestors: %w", err)
+	}
+
+	return ancestors, nil
+}
+
+func (r *commentResolver) Attachments(ctx context.Context, obj *model.Comment) ([]*model.Attachment, error) {
+	commentId, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, NewInputError("invalid comment ID format")
+	}
+
+	attachments, err := r.AttachmentService.GetByComment(ctx, commentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *commentResolver) History(ctx context.Context, obj *model.Comment, viewerID string) ([]*model.CommentEdit, error) {
+	commentId, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, NewInputError("invalid comment ID format")
+	}
+
+	viewerId, err := uuid.Parse(viewerID)
+	if err != nil {
+		return nil, NewInputError("invalid viewer ID format")
+	}
+
+	history, err := r.CommentService.GetHistory(ctx, commentId, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (r *commentResolver) Content(ctx context.Context, obj *model.Comment, format *model.ContentFormat) (string, error) {
+	return r.renderContent(obj.ID, obj.Content, format), nil
+}
+
 func (r *Resolver) Comment() graph.CommentResolver { return &commentResolver{r} }
 
 type commentResolver struct{ *Resolver }
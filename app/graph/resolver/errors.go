@@ -0,0 +1,15 @@
+package resolver
+
+// InputError reports a malformed argument that the schema types cannot catch,
+// such as an ID that is not a UUID.
+type InputError struct {
+	message string
+}
+
+func NewInputError(message string) *InputError {
+	return &InputError{message: message}
+}
+
+func (e *InputError) Error() string {
+	return e.message
+}
@@ -3,119 +3,264 @@ package resolver
 import (
 	"app/graph"
 	"app/graph/model"
+	"app/internal/service"
 	"context"
-	"errors"
-	"fmt"
-	"log"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/google/uuid"
 )
 
 func (r *mutationResolver) CreateUser(ctx context.Context, username string) (*model.User, error) {
-	start := time.Now()
-	log.Printf("Creating user with username: %s", username)
+	r.Logger.DebugContext(ctx, "Creating user", "username", username)
 
-	user, err := r.UserService.CreateUser(ctx, username)
+	return r.UserService.CreateUser(ctx, username)
+}
+
+func (r *mutationResolver) UpdateProfile(ctx context.Context, userID string, displayName *string, bio *string, avatar *graphql.Upload) (*model.User, error) {
+	r.Logger.DebugContext(ctx, "Updating profile", "user_id", userID, "avatar", avatar != nil)
+
+	userId, err := uuid.Parse(userID)
 	if err != nil {
-		log.Printf("Error creating user %s: %v", username, err)
-	} else {
-		log.Printf("Successfully created user %s with ID %s in %v", username, user.ID, time.Since(start))
+		return nil, NewInputError("invalid user ID format")
 	}
 
-	return user, err
+	var avatarUpload *service.FileUpload
+	if avatar != nil {
+		avatarUpload = newFileUpload(avatar)
+	}
+
+	return r.UserService.UpdateProfile(ctx, userId, displayName, bio, avatarUpload)
 }
 
 func (r *mutationResolver) CreatePost(ctx context.Context, userID string, title string, content string, isCommentable bool) (*model.Post, error) {
-	start := time.Now()
-	log.Printf("Creating post for user %s, title: %s, commentable: %v", userID, title, isCommentable)
+	r.Logger.DebugContext(ctx, "Creating post", "user_id", userID, "title", title, "commentable", isCommentable)
 
 	parsedUserId, err := uuid.Parse(userID)
 	if err != nil {
-		log.Printf("Invalid user ID format: %s, error: %v", userID, err)
-		return nil, fmt.Errorf("invalid user ID format")
+		return nil, NewInputError("invalid user ID format")
 	}
 
 	post, err := r.PostService.CreatePost(ctx, parsedUserId, title, content, isCommentable)
 	if err != nil {
-		log.Printf("Error creating post: %v", err)
-	} else {
-		log.Printf("Successfully created post with ID %s in %v", post.ID, time.Since(start))
+		return nil, err
+	}
+
+	r.publishPost(ctx, post)
+
+	return post, nil
+}
+
+func (r *mutationResolver) EditPost(ctx context.Context, postID string, userID string, title string, content string) (*model.Post, error) {
+	r.Logger.DebugContext(ctx, "Editing post", "post_id", postID, "user_id", userID, "title", title)
+
+	postId, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, NewInputError("invalid post ID format")
+	}
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	return r.PostService.EditPost(ctx, postId, userId, title, content)
+}
+
+func (r *mutationResolver) SaveDraft(ctx context.Context, userID string, postID *string, title string, content string, isCommentable bool) (*model.Post, error) {
+	r.Logger.DebugContext(ctx, "Saving draft", "post_id", optional(postID), "user_id", userID, "title", title)
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	var postId *uuid.UUID
+	if postID != nil {
+		parsedPostId, err := uuid.Parse(*postID)
+		if err != nil {
+			return nil, NewInputError("invalid post ID format")
+		}
+		postId = &parsedPostId
+	}
+
+	return r.PostService.SaveDraft(ctx, userId, postId, title, content, isCommentable)
+}
+
+func (r *mutationResolver) PublishPost(ctx context.Context, postID string, userID string) (*model.Post, error) {
+	r.Logger.DebugContext(ctx, "Publishing post", "post_id", postID, "user_id", userID)
+
+	postId, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, NewInputError("invalid post ID format")
+	}
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	post, err := r.PostService.PublishPost(ctx, postId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	r.publishPost(ctx, post)
+
+	return post, nil
+}
+
+func (r *mutationResolver) SchedulePost(ctx context.Context, postID string, userID string, publishAt time.Time) (*model.Post, error) {
+	r.Logger.DebugContext(ctx, "Scheduling post", "post_id", postID, "user_id", userID, "publish_at", publishAt)
+
+	postId, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, NewInputError("invalid post ID format")
+	}
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
 	}
 
-	return post, err
+	return r.PostService.SchedulePost(ctx, postId, userId, publishAt)
 }
 
 func (r *mutationResolver) CreateComment(ctx context.Context, userID string, postID string, parentID *string, content string) (*model.Comment, error) {
-	start := time.Now()
-	log.Printf("Creating comment by user %s on post %s (parent: %v)", userID, postID, parentID)
+	r.Logger.DebugContext(ctx, "Creating comment", "user_id", userID, "post_id", postID, "parent_id", optional(parentID))
 
 	userId, err := uuid.Parse(userID)
 	if err != nil {
-		log.Printf("Invalid user ID format: %s, error: %v", userID, err)
-		return nil, errors.New("invalid user ID format")
+		return nil, NewInputError("invalid user ID format")
 	}
 
 	postId, err := uuid.Parse(postID)
 	if err != nil {
-		log.Printf("Invalid post ID format: %s, error: %v", postID, err)
-		return nil, errors.New("invalid post ID format")
+		return nil, NewInputError("invalid post ID format")
 	}
 
 	var parentId *uuid.UUID
 	if parentID != nil {
 		parsedParentID, err := uuid.Parse(*parentID)
 		if err != nil {
-			log.Printf("Invalid parent comment ID format: %s, error: %v", *parentID, err)
-			return nil, errors.New("invalid parent comment ID format")
+			return nil, NewInputError("invalid parent comment ID format")
 		}
 		parentId = &parsedParentID
 	}
 
 	comment, err := r.CommentService.CreateComment(ctx, userId, postId, parentId, content)
 	if err != nil {
-		log.Printf("Error creating comment: %v", err)
 		return nil, err
 	}
 
 	if err := r.PubSubClient.PublishComment(ctx, postId, comment); err != nil {
-		log.Printf("Failed to publish comment: %v", err)
-	} else {
-		log.Printf("Successfully published comment %s to post %s", comment.ID, postID)
+		r.Logger.ErrorContext(ctx, "Failed to notify about comment", "comment_id", comment.ID, "error", err)
 	}
 
-	log.Printf("Successfully created comment %s in %v", comment.ID, time.Since(start))
 	return comment, nil
 }
 
-func (r *mutationResolver) TogglePostComments(ctx context.Context, postID string, editor string, enabled bool) (string, error) {
-	start := time.Now()
-	action := "enable"
-	if !enabled {
-		action = "disable"
+func (r *mutationResolver) EditComment(ctx context.Context, commentID string, userID string, content string) (*model.Comment, error) {
+	r.Logger.DebugContext(ctx, "Editing comment", "comment_id", commentID, "user_id", userID)
+
+	commentId, err := uuid.Parse(commentID)
+	if err != nil {
+		return nil, NewInputError("invalid comment ID format")
+	}
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	return r.CommentService.EditComment(ctx, commentId, userId, content)
+}
+
+func (r *mutationResolver) LockThread(ctx context.Context, commentID string, userID string) (*model.Comment, error) {
+	return r.setThreadLocked(ctx, commentID, userID, true)
+}
+
+func (r *mutationResolver) UnlockThread(ctx context.Context, commentID string, userID string) (*model.Comment, error) {
+	return r.setThreadLocked(ctx, commentID, userID, false)
+}
+
+func (r *mutationResolver) setThreadLocked(ctx context.Context, commentID string, userID string, locked bool) (*model.Comment, error) {
+	r.Logger.DebugContext(ctx, "Setting thread lock", "comment_id", commentID, "user_id", userID, "locked", locked)
+
+	commentId, err := uuid.Parse(commentID)
+	if err != nil {
+		return nil, NewInputError("invalid comment ID format")
+	}
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	return r.CommentService.SetThreadLocked(ctx, commentId, userId, locked)
+}
+
+func (r *mutationResolver) AddPostAttachment(ctx context.Context, postID string, userID string, file graphql.Upload) (*model.Attachment, error) {
+	r.Logger.DebugContext(ctx, "Adding post attachment", "filename", file.Filename, "post_id", postID, "user_id", userID)
+
+	postId, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, NewInputError("invalid post ID format")
+	}
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	return r.AttachmentService.AddPostAttachment(ctx, postId, userId, newFileUpload(&file))
+}
+
+func (r *mutationResolver) AddCommentAttachment(ctx context.Context, commentID string, userID string, file graphql.Upload) (*model.Attachment, error) {
+	r.Logger.DebugContext(ctx, "Adding comment attachment", "filename", file.Filename, "comment_id", commentID, "user_id", userID)
+
+	commentId, err := uuid.Parse(commentID)
+	if err != nil {
+		return nil, NewInputError("invalid comment ID format")
 	}
-	log.Printf("Attempting to %s comments for post %s by editor %s", action, postID, editor)
+
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	return r.AttachmentService.AddCommentAttachment(ctx, commentId, userId, newFileUpload(&file))
+}
+
+func (r *mutationResolver) TogglePostComments(ctx context.Context, postID string, editor string, enabled bool) (string, error) {
+	r.Logger.DebugContext(ctx, "Toggling post comments", "post_id", postID, "editor", editor, "enabled", enabled)
 
 	parsedPostID, err := uuid.Parse(postID)
 	if err != nil {
-		log.Printf("Invalid post ID format: %s, error: %v", postID, err)
-		return postID, fmt.Errorf("invalid post ID format")
+		return postID, NewInputError("invalid post ID format")
 	}
 
 	parsedEditorId, err := uuid.Parse(editor)
 	if err != nil {
-		log.Printf("Invalid editor ID format: %s, error: %v", editor, err)
-		return postID, fmt.Errorf("invalid editor ID format")
+		return postID, NewInputError("invalid editor ID format")
 	}
 
-	err = r.PostService.TogglePostComments(ctx, parsedPostID, parsedEditorId, enabled)
-	if err != nil {
-		log.Printf("Error toggling comments for post %s: %v", postID, err)
-	} else {
-		log.Printf("Successfully %sd comments for post %s in %v", action, postID, time.Since(start))
+	return postID, r.PostService.TogglePostComments(ctx, parsedPostID, parsedEditorId, enabled)
+}
+
+func (r *mutationResolver) publishPost(ctx context.Context, post *model.Post) {
+	if err := r.PubSubClient.PublishPost(ctx, post); err != nil {
+		r.Logger.ErrorContext(ctx, "Failed to notify about published post", "post_id", post.ID, "error", err)
 	}
+}
 
-	return postID, err
+func newFileUpload(upload *graphql.Upload) *service.FileUpload {
+	return &service.FileUpload{
+		Filename:    upload.Filename,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		Content:     upload.File,
+	}
 }
 
 type mutationResolver struct{ *Resolver }
@@ -5,32 +5,65 @@ import (
 	"app/graph/model"
 	"context"
 	"fmt"
-	"log"
-	"time"
 
 	"github.com/google/uuid"
 )
 
 func (r *postResolver) Comments(ctx context.Context, obj *model.Post, limit int32, offset int32) ([]*model.Comment, error) {
-	start := time.Now()
-	log.Printf("Resolving Comments for post %s with limit %d, offset %d", obj.ID, limit, offset)
+	r.Logger.DebugContext(ctx, "Resolving post comments", "post_id", obj.ID, "limit", limit, "offset", offset)
 
 	postID, err := uuid.Parse(obj.ID)
 	if err != nil {
-		log.Printf("Invalid post ID format: %s, error: %v", obj.ID, err)
 		return nil, fmt.Errorf("invalid post id: %w", err)
 	}
 
-	comments, err := r.CommentService.GetByPost(ctx, postID, int(limit), int(offset))
+	comments, err := r.CommentService.GetByPost(ctx, postID, int(r.pageSize(limit)), int(offset))
 	if err != nil {
-		log.Printf("Error fetching comments for post %s: %v", obj.ID, err)
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
 
-	log.Printf("Successfully fetched %d comments for post %s in %v", len(comments), obj.ID, time.Since(start))
 	return comments, nil
 }
 
+func (r *postResolver) Attachments(ctx context.Context, obj *model.Post) ([]*model.Attachment, error) {
+	postID, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post id: %w", err)
+	}
+
+	attachments, err := r.AttachmentService.GetByPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *postResolver) Revisions(ctx context.Context, obj *model.Post, first int32, after *string) ([]*model.PostRevision, error) {
+	r.Logger.DebugContext(ctx, "Resolving post revisions", "post_id", obj.ID, "first", first, "after", optional(after))
+
+	postID, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post id: %w", err)
+	}
+
+	afterId, err := parseCursor(after)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := r.PostService.GetRevisions(ctx, postID, int(r.pageSize(first)), afterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *postResolver) Content(ctx context.Context, obj *model.Post, format *model.ContentFormat) (string, error) {
+	return r.renderContent(obj.ID, obj.Content, format), nil
+}
+
 func (r *Resolver) Post() graph.PostResolver { return &postResolver{r} }
 
 type postResolver struct{ *Resolver }
@@ -4,89 +4,108 @@ import (
 	"app/graph"
 	"app/graph/model"
 	"context"
-	"fmt"
-	"log"
-	"time"
 
 	"github.com/google/uuid"
 )
 
 func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
-	start := time.Now()
-	log.Printf("Resolving User query with ID: %s", id)
+	r.Logger.DebugContext(ctx, "Resolving user", "id", id)
 
 	userId, err := uuid.Parse(id)
 	if err != nil {
-		log.Printf("Invalid user ID format: %s, error: %v", id, err)
-		return nil, fmt.Errorf("invalid ID format")
+		return nil, NewInputError("invalid ID format")
 	}
 
-	user, err := r.UserService.GetUser(ctx, userId)
+	return r.UserService.GetUser(ctx, userId)
+}
+
+func (r *queryResolver) UserByUsername(ctx context.Context, username string) (*model.User, error) {
+	r.Logger.DebugContext(ctx, "Resolving user by username", "username", username)
+
+	return r.UserService.GetUserByUsername(ctx, username)
+}
+
+func (r *queryResolver) Post(ctx context.Context, id string, viewerID *string) (*model.Post, error) {
+	r.Logger.DebugContext(ctx, "Resolving post", "id", id)
+
+	postId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, NewInputError("invalid post ID format")
+	}
+
+	viewerId, err := parseViewerId(viewerID)
 	if err != nil {
-		log.Printf("Error fetching user with ID %s: %v", id, err)
-	} else {
-		log.Printf("Successfully fetched user with ID %s in %v", id, time.Since(start))
+		return nil, err
 	}
 
-	return user, err
+	return r.PostService.GetPostById(ctx, postId, viewerId)
 }
 
-func (r *queryResolver) Post(ctx context.Context, id string) (*model.Post, error) {
-	start := time.Now()
-	log.Printf("Resolving Post query with ID: %s", id)
+func (r *queryResolver) Revision(ctx context.Context, id string, viewerID *string) (*model.PostRevision, error) {
+	r.Logger.DebugContext(ctx, "Resolving revision", "id", id)
 
-	postId, err := uuid.Parse(id)
+	revisionId, err := uuid.Parse(id)
 	if err != nil {
-		log.Printf("Invalid post ID format: %s, error: %v", id, err)
-		return nil, fmt.Errorf("invalid post ID format")
+		return nil, NewInputError("invalid revision ID format")
 	}
 
-	post, err := r.PostService.GetPostById(ctx, postId)
+	viewerId, err := parseViewerId(viewerID)
 	if err != nil {
-		log.Printf("Error fetching post with ID %s: %v", id, err)
-	} else {
-		log.Printf("Successfully fetched post with ID %s in %v", id, time.Since(start))
+		return nil, err
 	}
 
-	return post, err
+	return r.PostService.GetRevision(ctx, revisionId, viewerId)
 }
 
-func (r *queryResolver) Replies(ctx context.Context, commentID string, limit int32, offset int32) ([]*model.Comment, error) {
-	start := time.Now()
-	log.Printf("Resolving Replies for commentID: %s, limit: %d, offset: %d", commentID, limit, offset)
+func (r *queryResolver) RevisionDiff(ctx context.Context, fromID string, toID *string, viewerID *string) (*model.PostDiff, error) {
+	r.Logger.DebugContext(ctx, "Resolving revision diff", "from_id", fromID, "to_id", optional(toID))
 
-	parentId, err := uuid.Parse(commentID)
+	fromId, err := uuid.Parse(fromID)
+	if err != nil {
+		return nil, NewInputError("invalid revision ID format")
+	}
+
+	var toId *uuid.UUID
+	if toID != nil {
+		parsedToId, err := uuid.Parse(*toID)
+		if err != nil {
+			return nil, NewInputError("invalid revision ID format")
+		}
+		toId = &parsedToId
+	}
+
+	viewerId, err := parseViewerId(viewerID)
 	if err != nil {
-		log.Printf("Invalid comment ID format: %s, error: %v", commentID, err)
-		return nil, fmt.Errorf("invalid comment ID format")
+		return nil, err
 	}
 
-	replies, err := r.CommentService.GetCommentReplies(ctx, parentId, int(limit), int(offset))
+	return r.PostService.DiffRevisions(ctx, fromId, toId, viewerId)
+}
+
+func (r *queryResolver) Replies(ctx context.Context, commentID string, limit int32, offset int32) ([]*model.Comment, error) {
+	r.Logger.DebugContext(ctx, "Resolving comment replies", "comment_id", commentID, "limit", limit, "offset", offset)
+
+	parentId, err := uuid.Parse(commentID)
 	if err != nil {
-		log.Printf("Error fetching replies for comment %s: %v", commentID, err)
-	} else {
-		log.Printf("Successfully fetched %d replies for comment %s in %v", len(replies), commentID, time.Since(start))
+		return nil, NewInputError("invalid comment ID format")
 	}
 
-	return replies, err
+	return r.CommentService.GetCommentReplies(ctx, parentId, int(r.pageSize(limit)), int(offset))
 }
 
-func (r *queryResolver) Posts(ctx context.Context, limit int32, offset int32, sortBy *model.SortBy) ([]*model.Post, error) {
-	start := time.Now()
+func (r *queryResolver) Posts(ctx context.Context, limit int32, offset int32, sortBy *model.SortBy, viewerID *string) ([]*model.Post, error) {
 	sort := "default"
 	if sortBy != nil {
 		sort = string(*sortBy)
 	}
-	log.Printf("Resolving Posts query with limit: %d, offset: %d, sortBy: %s", limit, offset, sort)
+	r.Logger.DebugContext(ctx, "Resolving posts", "limit", limit, "offset", offset, "sort_by", sort)
 
-	posts, err := r.PostService.GetPosts(ctx, int(limit), int(offset), sortBy)
+	viewerId, err := parseViewerId(viewerID)
 	if err != nil {
-		log.Printf("Error fetching posts: %v", err)
-	} else {
-		log.Printf("Successfully fetched %d posts in %v", len(posts), time.Since(start))
+		return nil, err
 	}
 
-	return posts, err
+	return r.PostService.GetPosts(ctx, int(r.pageSize(limit)), int(offset), sortBy, viewerId)
 }
 
 func (r *Resolver) Query() graph.QueryResolver { return &queryResolver{r} }
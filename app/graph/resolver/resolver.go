@@ -1,13 +1,47 @@
 package resolver
 
 import (
+	"app/graph/model"
+	"app/internal/blobstore"
+	"app/internal/markdown"
 	"app/internal/pubsub"
 	"app/internal/service"
+	"log/slog"
 )
 
 type Resolver struct {
-	UserService    service.User
-	PostService    service.Post
-	CommentService service.Comment
-	PubSubClient   pubsub.PubSubClient
+	UserService       service.User
+	PostService       service.Post
+	CommentService    service.Comment
+	AttachmentService service.Attachment
+	PubSubClient      pubsub.PubSubClient
+	BlobStore         blobstore.BlobStore
+	ContentCache      *markdown.Cache
+	Logger            *slog.Logger
+
+	// MaxPageSize caps limit and first arguments; zero leaves them as is.
+	MaxPageSize int32
+}
+
+func (r *Resolver) pageSize(n int32) int32 {
+	if r.MaxPageSize > 0 && n > r.MaxPageSize {
+		return r.MaxPageSize
+	}
+	return n
+}
+
+func (r *Resolver) renderContent(id string, content string, format *model.ContentFormat) string {
+	if format == nil || *format == model.ContentFormatRaw {
+		return content
+	}
+	return r.ContentCache.Render(id, content, markdown.Format(*format))
+}
+
+// optional returns the value of an optional argument for logging, so that
+// log lines show the value rather than the address of the pointer.
+func optional[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return *v
 }
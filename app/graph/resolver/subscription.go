@@ -4,7 +4,6 @@ import (
 	"app/graph"
 	"app/graph/model"
 	"context"
-	"fmt"
 
 	"github.com/google/uuid"
 )
@@ -12,12 +11,16 @@ import (
 func (r *subscriptionResolver) CommentAdded(ctx context.Context, postID string) (<-chan *model.Comment, error) {
 	postId, err := uuid.Parse(postID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid postID format: %w", err)
+		return nil, NewInputError("invalid postID format: " + err.Error())
 	}
 
 	return r.PubSubClient.SubscribeOnComments(ctx, postId)
 }
 
+func (r *subscriptionResolver) PostPublished(ctx context.Context) (<-chan *model.Post, error) {
+	return r.PubSubClient.SubscribeOnPosts(ctx)
+}
+
 func (r *Resolver) Subscription() graph.SubscriptionResolver { return &subscriptionResolver{r} }
 
 type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"app/graph"
+	"app/graph/model"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (r *userResolver) Posts(ctx context.Context, obj *model.User, first int32, after *string, viewerID *string) ([]*model.Post, error) {
+	r.Logger.DebugContext(ctx, "Resolving user posts", "user_id", obj.ID, "first", first, "after", optional(after))
+
+	userId, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	afterId, err := parseCursor(after)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerId, err := parseViewerId(viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := r.PostService.GetPostsByUser(ctx, userId, int(r.pageSize(first)), afterId, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r *userResolver) Comments(ctx context.Context, obj *model.User, first int32, after *string) ([]*model.Comment, error) {
+	r.Logger.DebugContext(ctx, "Resolving user comments", "user_id", obj.ID, "first", first, "after", optional(after))
+
+	userId, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	afterId, err := parseCursor(after)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := r.CommentService.GetByUser(ctx, userId, int(r.pageSize(first)), afterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *userResolver) Stats(ctx context.Context, obj *model.User) (*model.UserStats, error) {
+	userId, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, NewInputError("invalid user ID format")
+	}
+
+	return r.UserService.GetUserStats(ctx, userId)
+}
+
+func (r *userResolver) AvatarURL(ctx context.Context, obj *model.User) (*string, error) {
+	if obj.AvatarKey == "" {
+		return nil, nil
+	}
+	url := r.BlobStore.URL(obj.AvatarKey)
+	return &url, nil
+}
+
+func parseCursor(after *string) (*uuid.UUID, error) {
+	if after == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*after)
+	if err != nil {
+		return nil, NewInputError("invalid cursor format")
+	}
+	return &id, nil
+}
+
+func parseViewerId(viewerID *string) (*uuid.UUID, error) {
+	if viewerID == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*viewerID)
+	if err != nil {
+		return nil, NewInputError("invalid viewer ID format")
+	}
+	return &id, nil
+}
+
+func (r *Resolver) User() graph.UserResolver { return &userResolver{r} }
+
+type userResolver struct{ *Resolver }
@@ -2,17 +2,33 @@ package app
 
 import (
 	"app/graph/resolver"
+	"app/internal/blobstore"
+	blobstore_local "app/internal/blobstore/local"
 	"app/internal/config"
+	"app/internal/health"
+	"app/internal/logging"
+	"app/internal/markdown"
+	"app/internal/metrics"
+	"app/internal/persisted"
 	"app/internal/pubsub"
 	pubsub_redis "app/internal/pubsub/redis"
+	"app/internal/ratelimit"
 	"app/internal/repository"
 	"app/internal/repository/inmemory"
 	"app/internal/repository/postgres"
 	"app/internal/service"
+	"app/internal/tracing"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/go-redis/redis/v8"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
@@ -21,58 +37,285 @@ type App struct {
 	Config     *config.Config
 	Resolver   *resolver.Resolver
 	HttpApp    *Server
+	Scheduler  *Scheduler
 	RepoHolder *repository.RepoHolder
+	Health     *health.Health
+
+	// ShutdownTracing flushes spans that have not been exported yet.
+	ShutdownTracing func(context.Context) error
+
+	pubsub  pubsub.PubSubClient
+	closeDB func()
 }
 
-const inmemoryRepoSize int = 50
+const (
+	inmemoryRepoSize int = 50
+	contentCacheSize int = 1000
+	rateLimitBuckets int = 100000
+	apqCacheSize     int = 1000
+)
 
 func NewApp(ctx context.Context, cfg *config.Config) *App {
-	repoHolder := initRepositories(ctx, cfg)
-	pubsub := initPubSub(cfg)
-	services := &service.Services{
-		User:    &service.UserService{RepoHolder: repoHolder},
-		Post:    &service.PostService{RepoHolder: repoHolder},
-		Comment: &service.CommentService{RepoHolder: repoHolder},
+	logger := initLogger(cfg)
+	shutdownTracing := initTracing(ctx, cfg)
+	appMetrics := metrics.New()
+	appMetrics.TrackOperations(cfg.MetricsConfig.Operations...)
+	repoHolder, repoCheck, closeDB := initRepositories(ctx, cfg, appMetrics, logger)
+	redisClient := initRedis(cfg)
+	pubsub := initPubSub(cfg, redisClient, appMetrics, logger)
+	limiter := initRateLimiter(cfg, redisClient, logger)
+	appHealth := health.New(logger, repoCheck, health.Check{Name: "redis", Check: pubsub.Ping})
+	blobStore := initBlobStore(cfg)
+	services := service.WithTracing(&service.Services{
+		User:       &service.UserService{RepoHolder: repoHolder, BlobStore: blobStore, Logger: logger},
+		Post:       &service.PostService{RepoHolder: repoHolder},
+		Comment:    &service.CommentService{RepoHolder: repoHolder, MaxDepth: cfg.CommentConfig.MaxDepth},
+		Attachment: &service.AttachmentService{RepoHolder: repoHolder, BlobStore: blobStore, Logger: logger},
+	})
+
+	contentCache, err := markdown.NewCache(contentCacheSize)
+	if err != nil {
+		log.Fatalf("failed to init content cache: %v", err)
 	}
 
 	resolver := &resolver.Resolver{
-		UserService:    services.User,
-		PostService:    services.Post,
-		CommentService: services.Comment,
-		PubSubClient:   pubsub,
+		UserService:       services.User,
+		PostService:       services.Post,
+		CommentService:    services.Comment,
+		AttachmentService: services.Attachment,
+		PubSubClient:      pubsub,
+		BlobStore:         blobStore,
+		ContentCache:      contentCache,
+		Logger:            logger,
+		MaxPageSize:       cfg.QueryLimitsConfig.MaxPageSize,
 	}
 
-	server := NewServer(cfg, resolver)
+	server := NewServer(cfg, resolver, blobStore, logger, appMetrics, appHealth, limiter, initPersistedQueries(cfg, redisClient, appMetrics, logger))
+	if cfg.SchedulerConfig.Interval <= 0 {
+		log.Fatalf("failed to init scheduler: interval must be positive")
+	}
+	scheduler := NewScheduler(services.Post, pubsub, cfg.SchedulerConfig.Interval, logger)
 
 	return &App{
 		Config:     cfg,
 		Resolver:   resolver,
 		HttpApp:    server,
+		Scheduler:  scheduler,
 		RepoHolder: repoHolder,
+		Health:     appHealth,
+
+		ShutdownTracing: shutdownTracing,
+
+		pubsub:  pubsub,
+		closeDB: closeDB,
+	}
+}
+
+// Shutdown stops the application in dependency order: the scheduler and the
+// server first, so that nothing publishes or queries any more, then the
+// connections to redis and the database, and finally the tracer, so that
+// spans of the previous steps are still exported. It carries on past
+// failing steps and returns all their errors.
+func (a *App) Shutdown(ctx context.Context) error {
+	a.Health.SetDraining()
+	a.Scheduler.Stop()
+
+	var errs []error
+	if err := a.HttpApp.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := a.pubsub.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close redis: %w", err))
+	}
+	a.closeDB()
+	if err := a.ShutdownTracing(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush traces: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// initLogger also makes the logger the default one, so that packages logging
+// through log or slog directly share its level and format.
+func initLogger(cfg *config.Config) *slog.Logger {
+	logger, err := logging.New(os.Stderr, cfg.LogConfig.Level, cfg.LogConfig.Format)
+	if err != nil {
+		log.Fatalf("failed to init logger: %v", err)
 	}
+	slog.SetDefault(logger)
+	return logger
 }
 
-func initRepositories(ctx context.Context, cfg *config.Config) *repository.RepoHolder {
-	switch cfg.DB.(type) {
+func initTracing(ctx context.Context, cfg *config.Config) func(context.Context) error {
+	shutdown, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     cfg.TracingConfig.Exporter,
+		OTLPEndpoint: cfg.TracingConfig.OTLPEndpoint,
+		ServiceName:  cfg.TracingConfig.ServiceName,
+		SampleRatio:  cfg.TracingConfig.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("failed to init tracing: %v", err)
+	}
+	return shutdown
+}
+
+// initRepositories also returns the function that releases the database.
+func initRepositories(ctx context.Context, cfg *config.Config, m *metrics.Metrics, logger *slog.Logger) (*repository.RepoHolder, health.Check, func()) {
+	switch dbConfig := cfg.DB.(type) {
 	case config.InMemoryConfig:
-		return inmemory.NewRepoHolder(inmemoryRepoSize)
+		return inmemory.NewRepoHolder(inmemoryRepoSize), health.Check{
+			Name:  "inmemory",
+			Check: func(context.Context) error { return nil },
+		}, func() {}
 	case config.PostgresConfig:
-		pool, err := pgxpool.Connect(ctx, cfg.DB.DSN())
-		if err != nil {
-			log.Fatalf("failed to connect to postgres: %v", err)
+		ctx, cancel := context.WithCancel(ctx)
+		pool := connectPostgres(ctx, dbConfig, logger)
+		m.Register(metrics.NewPoolCollector(pool))
+		db := postgres.WithRetry(postgres.WithTracing(pool), dbConfig.RetryAttempts, dbConfig.RetryDelay, logger)
+		closeDB := func() {
+			cancel()
+			pool.Close()
 		}
-		return postgres.NewRepoHolder(pool)
+		return postgres.NewRepoHolder(db), health.Check{Name: "postgres", Check: pool.Ping}, closeDB
 	default:
 		log.Fatal("Unsupported database type")
-		return nil
+		return nil, health.Check{}, nil
+	}
+}
+
+// connectPostgres creates the pool without waiting for the database, so the
+// server starts and reports itself not ready until postgres comes up.
+func connectPostgres(ctx context.Context, cfg config.PostgresConfig, logger *slog.Logger) *pgxpool.Pool {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN())
+	if err != nil {
+		log.Fatalf("invalid postgres config: %v", err)
+	}
+	poolConfig.LazyConnect = true
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		log.Fatalf("failed to create postgres pool: %v", err)
+	}
+
+	go waitForPostgres(ctx, pool, cfg.ConnectRetryDelay, cfg.ConnectRetryMaxDelay, logger)
+	return pool
+}
+
+// waitForPostgres pings the database with a doubling delay until it answers.
+func waitForPostgres(ctx context.Context, pool *pgxpool.Pool, delay, maxDelay time.Duration, logger *slog.Logger) {
+	for attempt := 1; ; attempt++ {
+		err := pool.Ping(ctx)
+		if err == nil {
+			logger.Info("Connected to postgres", "attempts", attempt)
+			return
+		}
+
+		logger.Warn("Postgres unavailable, retrying", "attempt", attempt, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxDelay)
 	}
 }
 
-func initPubSub(cfg *config.Config) pubsub.PubSubClient {
-	redisClient := redis.NewClient(&redis.Options{
+func initRedis(cfg *config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", cfg.RedisConfig.Host, cfg.RedisConfig.Port),
 		Password: cfg.RedisConfig.Password,
 		DB:       cfg.RedisConfig.DB,
 	})
-	return pubsub_redis.NewRedisPubSub(redisClient)
+}
+
+func initPubSub(cfg *config.Config, redisClient *redis.Client, m *metrics.Metrics, logger *slog.Logger) pubsub.PubSubClient {
+	policy, err := pubsub.ParseOverflowPolicy(cfg.SubscriptionConfig.OverflowPolicy)
+	if err != nil {
+		log.Fatalf("failed to init pubsub: %v", err)
+	}
+	if cfg.SubscriptionConfig.BufferSize < 1 {
+		log.Fatalf("failed to init pubsub: buffer size must be positive")
+	}
+
+	pubsub := pubsub_redis.NewRedisPubSub(redisClient, pubsub.Backpressure{
+		BufferSize: cfg.SubscriptionConfig.BufferSize,
+		Policy:     policy,
+	}, logger)
+	m.Register(metrics.NewCommentCollector(pubsub))
+	return pubsub
+}
+
+// initRateLimiter returns nil when rate limiting is disabled.
+func initRateLimiter(cfg *config.Config, redisClient *redis.Client, logger *slog.Logger) *ratelimit.Limiter {
+	rlConfig := cfg.RateLimitConfig
+	if !rlConfig.Enabled {
+		return nil
+	}
+
+	limiter := &ratelimit.Limiter{
+		Mutation:                      mustParseLimit(rlConfig.Mutation),
+		Subscription:                  mustParseLimit(rlConfig.Subscription),
+		Fields:                        make(map[string]ratelimit.Limit, len(rlConfig.Fields)),
+		MaxSubscriptionsPerConnection: rlConfig.MaxSubscriptionsPerConnection,
+		Logger:                        logger,
+	}
+	for field, limit := range rlConfig.Fields {
+		limiter.Fields[field] = mustParseLimit(limit)
+	}
+
+	switch rlConfig.Store {
+	case "memory":
+		store, err := ratelimit.NewMemoryStore(rateLimitBuckets)
+		if err != nil {
+			log.Fatalf("failed to init rate limit store: %v", err)
+		}
+		limiter.Store = store
+	case "redis":
+		limiter.Store = ratelimit.NewRedisStore(redisClient)
+	default:
+		log.Fatalf("unknown rate limit store: %s", rlConfig.Store)
+	}
+
+	return limiter
+}
+
+// initPersistedQueries returns the trusted documents allowlist when it is
+// enabled, and automatic persisted queries otherwise. Operations of the
+// allowlist are tracked by name in metrics.
+func initPersistedQueries(cfg *config.Config, redisClient *redis.Client, m *metrics.Metrics, logger *slog.Logger) graphql.HandlerExtension {
+	pqConfig := cfg.PersistedQueryConfig
+	if pqConfig.Allowlist {
+		manifest, err := persisted.LoadManifest(pqConfig.ManifestPath)
+		if err != nil {
+			log.Fatalf("failed to load persisted queries: %v", err)
+		}
+		logger.Info("Persisted query allowlist enabled", "operations", len(manifest))
+		m.TrackOperations(manifest.OperationNames()...)
+		return persisted.Allowlist{Manifest: manifest}
+	}
+
+	switch pqConfig.APQStore {
+	case "memory":
+		return extension.AutomaticPersistedQuery{Cache: lru.New[string](apqCacheSize)}
+	case "redis":
+		return extension.AutomaticPersistedQuery{Cache: persisted.NewRedisCache(redisClient, pqConfig.APQTTL, pqConfig.APQMaxEntries, pqConfig.APQMaxQuerySize, logger)}
+	default:
+		log.Fatalf("unknown APQ store: %s", pqConfig.APQStore)
+		return nil
+	}
+}
+
+func mustParseLimit(s string) ratelimit.Limit {
+	limit, err := ratelimit.ParseLimit(s)
+	if err != nil {
+		log.Fatalf("failed to load rate limits: %v", err)
+	}
+	return limit
+}
+
+func initBlobStore(cfg *config.Config) blobstore.BlobStore {
+	store, err := blobstore_local.NewLocalBlobStore(cfg.BlobStoreConfig.Dir, cfg.BlobStoreConfig.BaseURL)
+	if err != nil {
+		log.Fatalf("failed to init blob store: %v", err)
+	}
+	return store
 }
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"app/graph/resolver"
+	"app/internal/entity"
+	"app/internal/persisted"
+	"app/internal/ratelimit"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes reported to clients in extensions.code.
+const (
+	CodeNotFound           = "NOT_FOUND"
+	CodeForbidden          = "FORBIDDEN"
+	CodeValidation         = "VALIDATION"
+	CodeConflict           = "CONFLICT"
+	CodeInternal           = "INTERNAL"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeShuttingDown       = "SHUTTING_DOWN"
+	CodeSubscriptionLagged = "SUBSCRIPTION_LAGGED"
+
+	CodeComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+	CodeDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+
+	CodePersistedQueryNotFound   = "PERSISTED_QUERY_NOT_FOUND"
+	CodePersistedQueryNotAllowed = persisted.CodeNotAllowed
+)
+
+const internalErrorMessage = "Internal server error"
+
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{service.ErrUserNotFound, CodeNotFound},
+	{service.ErrPostNotFound, CodeNotFound},
+	{service.ErrRevisionNotFound, CodeNotFound},
+	{service.ErrCommentNotFound, CodeNotFound},
+	{service.ErrParentCommentNotFound, CodeNotFound},
+	{repository.ErrNotFound, CodeNotFound},
+
+	{service.ErrNoPermissionForToggle, CodeForbidden},
+	{service.ErrNoPermissionForPost, CodeForbidden},
+	{service.ErrNoPermissionForComment, CodeForbidden},
+	{service.ErrNoPermissionForHistory, CodeForbidden},
+	{service.ErrNoPermissionForLock, CodeForbidden},
+	{service.ErrNoPermissionForAttachment, CodeForbidden},
+	{errIntrospectionDisabled, CodeForbidden},
+
+	{service.ErrUsernameExists, CodeConflict},
+	{service.ErrPostIsNotCommentable, CodeConflict},
+	{service.ErrThreadLocked, CodeConflict},
+	{entity.ErrPostAlreadyPublished, CodeConflict},
+
+	{ratelimit.ErrRateLimited, CodeRateLimited},
+	{ratelimit.ErrTooManySubscriptions, CodeRateLimited},
+
+	{service.ErrTooManySymbols, CodeValidation},
+	{service.ErrParentOnDifferentPost, CodeValidation},
+	{service.ErrMaxDepthExceeded, CodeValidation},
+	{service.ErrRevisionsMismatch, CodeValidation},
+	{service.ErrAvatarTooLarge, CodeValidation},
+	{service.ErrUnsupportedAvatarType, CodeValidation},
+	{service.ErrAttachmentTooLarge, CodeValidation},
+	{service.ErrUnsupportedAttachmentType, CodeValidation},
+	{service.ErrTooManyAttachments, CodeValidation},
+	{repository.ErrParentOnDifferentPost, CodeValidation},
+	{entity.ErrEmptyUsername, CodeValidation},
+	{entity.ErrEmptyTitle, CodeValidation},
+	{entity.ErrEmptyContent, CodeValidation},
+	{entity.ErrInvalidUserID, CodeValidation},
+	{entity.ErrInvalidPostID, CodeValidation},
+	{entity.ErrCommentTooLong, CodeValidation},
+	{entity.ErrInvalidCommentPath, CodeValidation},
+	{entity.ErrRenderedContentTooLarge, CodeValidation},
+	{entity.ErrInvalidPostStatus, CodeValidation},
+	{entity.ErrMissingPublishTime, CodeValidation},
+	{entity.ErrPublishTimeInPast, CodeValidation},
+	{entity.ErrDisplayNameTooLong, CodeValidation},
+	{entity.ErrBioTooLong, CodeValidation},
+	{entity.ErrInvalidAttachmentOwner, CodeValidation},
+	{entity.ErrEmptyFilename, CodeValidation},
+	{entity.ErrFilenameTooLong, CodeValidation},
+}
+
+// presentError sets extensions.code on every error sent to clients. Errors
+// that match no known code are replaced by a generic message, and the
+// original is logged under a correlation ID returned to the client.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr == nil {
+		return nil
+	}
+
+	code, ok := errorCode(ctx, gqlErr)
+	if !ok {
+		correlationId := uuid.New().String()
+		slog.ErrorContext(ctx, "Internal error", "correlation_id", correlationId, "path", gqlErr.Path.String(), "error", err)
+
+		return &gqlerror.Error{
+			Message:    internalErrorMessage,
+			Path:       gqlErr.Path,
+			Locations:  gqlErr.Locations,
+			Extensions: map[string]interface{}{"code": CodeInternal, "correlationId": correlationId},
+		}
+	}
+
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = make(map[string]interface{})
+	}
+	var extended extendedError
+	if errors.As(err, &extended) {
+		for key, value := range extended.Extensions() {
+			gqlErr.Extensions[key] = value
+		}
+	}
+	gqlErr.Extensions["code"] = code
+	return gqlErr
+}
+
+// errorPresenter returns presentError, additionally restoring the text of
+// masked errors when verbose is set.
+func errorPresenter(verbose bool) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := presentError(ctx, err)
+		if verbose && gqlErr != nil && gqlErr.Extensions["code"] == CodeInternal {
+			gqlErr.Message = err.Error()
+		}
+		return gqlErr
+	}
+}
+
+// extendedError is implemented by errors that carry details for clients,
+// such as how long to wait before retrying.
+type extendedError interface {
+	error
+	Extensions() map[string]interface{}
+}
+
+func errorCode(ctx context.Context, gqlErr *gqlerror.Error) (string, bool) {
+	// Parse and validation errors are built by gqlgen itself and wrap nothing;
+	// the query limits and persisted queries keep their own, more specific
+	// codes, which APQ clients rely on.
+	if gqlErr.Err == nil {
+		switch code := gqlErr.Extensions["code"]; code {
+		case CodeComplexityLimit, CodeDepthLimit, CodePersistedQueryNotFound, CodePersistedQueryNotAllowed:
+			return code.(string), true
+		}
+		return CodeValidation, true
+	}
+
+	for _, known := range errorCodes {
+		if errors.Is(gqlErr.Err, known.err) {
+			return known.code, true
+		}
+	}
+
+	var inputErr *resolver.InputError
+	if errors.As(gqlErr.Err, &inputErr) {
+		return CodeValidation, true
+	}
+
+	// Arguments are decoded before the resolver runs, so a field that takes
+	// arguments but has none set failed while decoding them.
+	if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Args == nil && len(fc.Field.Arguments) > 0 {
+		return CodeValidation, true
+	}
+
+	return "", false
+}
+
+// recoverPanic logs the stack of a panicking resolver; the returned error
+// carries no known code, so presentError masks it as INTERNAL.
+func recoverPanic(ctx context.Context, p interface{}) error {
+	slog.ErrorContext(ctx, "Recovered from panic", "panic", p, "stack", string(debug.Stack()))
+	return fmt.Errorf("panic: %v", p)
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"app/graph/resolver"
+	"app/internal/entity"
+	"app/internal/ratelimit"
+	"app/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestPresentError(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		err     error
+		code    string
+		message string
+	}{
+		{"not found", fmt.Errorf("failed to get post: %w", service.ErrPostNotFound), CodeNotFound, "failed to get post: Post not found"},
+		{"forbidden", service.ErrNoPermissionForToggle, CodeForbidden, "Only creator can toggle comments"},
+		{"conflict", service.ErrThreadLocked, CodeConflict, "Thread is locked"},
+		{"entity validation", fmt.Errorf("Validation error: %w", entity.ErrEmptyTitle), CodeValidation, "Validation error: post title cannot be empty"},
+		{"malformed id", resolver.NewInputError("invalid post ID format"), CodeValidation, "invalid post ID format"},
+		{"query validation", &gqlerror.Error{Message: "Cannot query field \"foo\" on type \"Query\"."}, CodeValidation, "Cannot query field \"foo\" on type \"Query\"."},
+		{"complexity limit", &gqlerror.Error{Message: "operation has complexity 9001, which exceeds the limit of 5000", Extensions: map[string]interface{}{"code": CodeComplexityLimit}}, CodeComplexityLimit, "operation has complexity 9001, which exceeds the limit of 5000"},
+		{"apq miss", &gqlerror.Error{Message: "PersistedQueryNotFound", Extensions: map[string]interface{}{"code": CodePersistedQueryNotFound}}, CodePersistedQueryNotFound, "PersistedQueryNotFound"},
+		{"depth limit", &gqlerror.Error{Message: "operation has depth 12, which exceeds the limit of 10", Extensions: map[string]interface{}{"code": CodeDepthLimit}}, CodeDepthLimit, "operation has depth 12, which exceeds the limit of 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := presentError(ctx, tt.err)
+			assert.Equal(t, tt.code, result.Extensions["code"])
+			assert.Equal(t, tt.message, result.Message)
+			assert.NotContains(t, result.Extensions, "correlationId")
+		})
+	}
+
+	t.Run("rate limited carries retry after", func(t *testing.T) {
+		result := presentError(ctx, &ratelimit.Error{RetryAfter: 1500 * time.Millisecond})
+		assert.Equal(t, CodeRateLimited, result.Extensions["code"])
+		assert.Equal(t, 2, result.Extensions["retryAfter"])
+		assert.Equal(t, "Rate limit exceeded, retry in 2s", result.Message)
+	})
+
+	t.Run("internal error is masked", func(t *testing.T) {
+		result := presentError(ctx, errors.New(`ERROR: relation "comments" does not exist (SQLSTATE 42P01)`))
+		assert.Equal(t, CodeInternal, result.Extensions["code"])
+		assert.Equal(t, internalErrorMessage, result.Message)
+		assert.NotEmpty(t, result.Extensions["correlationId"])
+	})
+
+	t.Run("verbose keeps internal error text", func(t *testing.T) {
+		result := errorPresenter(true)(ctx, errors.New(`ERROR: relation "comments" does not exist (SQLSTATE 42P01)`))
+		assert.Equal(t, CodeInternal, result.Extensions["code"])
+		assert.Equal(t, `ERROR: relation "comments" does not exist (SQLSTATE 42P01)`, result.Message)
+		assert.NotEmpty(t, result.Extensions["correlationId"])
+	})
+
+	t.Run("introspection disabled", func(t *testing.T) {
+		result := presentError(ctx, errIntrospectionDisabled)
+		assert.Equal(t, CodeForbidden, result.Extensions["code"])
+		assert.Equal(t, "introspection disabled", result.Message)
+	})
+
+	t.Run("panic is masked", func(t *testing.T) {
+		result := presentError(ctx, recoverPanic(ctx, "nil pointer dereference"))
+		assert.Equal(t, CodeInternal, result.Extensions["code"])
+		assert.Equal(t, internalErrorMessage, result.Message)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, presentError(ctx, nil))
+	})
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// AdminTokenHeader carries the token that unlocks introspection for internal
+// tools where the profile disables it.
+const AdminTokenHeader = "X-Admin-Token"
+
+// errIntrospectionDisabled answers __schema and __type when introspection is
+// off, before the generated resolvers get to return their untyped error.
+var errIntrospectionDisabled = errors.New("introspection disabled")
+
+// Introspection enables introspection for every operation when Enabled is
+// set, and otherwise only for operations sent with AdminToken.
+type Introspection struct {
+	Enabled    bool
+	AdminToken string
+}
+
+var (
+	_ graphql.HandlerExtension        = Introspection{}
+	_ graphql.OperationContextMutator = Introspection{}
+	_ graphql.FieldInterceptor        = Introspection{}
+)
+
+func (i Introspection) ExtensionName() string {
+	return "Introspection"
+}
+
+func (i Introspection) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (i Introspection) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if i.Enabled || i.hasAdminToken(opCtx) {
+		opCtx.DisableIntrospection = false
+	}
+	return nil
+}
+
+func (i Introspection) hasAdminToken(opCtx *graphql.OperationContext) bool {
+	if i.AdminToken == "" {
+		return false
+	}
+	token := opCtx.Headers.Get(AdminTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(i.AdminToken)) == 1
+}
+
+func (i Introspection) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc != nil && fc.Object == "Query" && (fc.Field.Name == "__schema" || fc.Field.Name == "__type") &&
+		graphql.GetOperationContext(ctx).DisableIntrospection {
+		return nil, errIntrospectionDisabled
+	}
+	return next(ctx)
+}
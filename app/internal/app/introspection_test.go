@@ -0,0 +1,66 @@
+package app
+
+import (
+	mock_pubsub "app/internal/pubsub/mocks"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospection(t *testing.T) {
+	tests := []struct {
+		name      string
+		extension Introspection
+		token     string
+		enabled   bool
+	}{
+		{"enabled by profile", Introspection{Enabled: true}, "", true},
+		{"disabled by profile", Introspection{}, "", false},
+		{"admin token", Introspection{AdminToken: "secret"}, "secret", true},
+		{"wrong admin token", Introspection{AdminToken: "secret"}, "guess", false},
+		{"no admin token configured", Introspection{}, "secret", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opCtx := &graphql.OperationContext{DisableIntrospection: true, Headers: http.Header{}}
+			if tt.token != "" {
+				opCtx.Headers.Set(AdminTokenHeader, tt.token)
+			}
+
+			assert.Nil(t, tt.extension.MutateOperationContext(context.Background(), opCtx))
+			assert.Equal(t, tt.enabled, !opCtx.DisableIntrospection)
+		})
+	}
+}
+
+func TestIntrospectionDisabledError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(newTestServer(t, mock_pubsub.NewMockPubSubClient(ctrl)).routes())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/query", "application/json", strings.NewReader(`{"query":"{ __schema { queryType { name } } }"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body struct {
+		Errors []struct {
+			Message    string         `json:"message"`
+			Extensions map[string]any `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "introspection disabled", body.Errors[0].Message)
+	assert.Equal(t, CodeForbidden, body.Errors[0].Extensions["code"])
+}
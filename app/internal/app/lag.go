@@ -0,0 +1,95 @@
+package app
+
+import (
+	"app/internal/pubsub"
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// subscriptionLagged is the response extension that announces skipped events.
+const subscriptionLagged = "subscriptionLagged"
+
+// SubscriptionLag tells subscribers that fell behind how many events they
+// missed, so that they can re-query. The notice is a synthetic event with
+// no data, sent before the first event that follows the gap:
+//
+//	{"data": null, "extensions": {"subscriptionLagged": {"skipped": 3}}}
+//
+// A subscriber disconnected for falling behind gets a SUBSCRIPTION_LAGGED
+// error instead, right before complete.
+type SubscriptionLag struct{}
+
+var (
+	_ graphql.HandlerExtension     = SubscriptionLag{}
+	_ graphql.OperationInterceptor = SubscriptionLag{}
+)
+
+func (SubscriptionLag) ExtensionName() string {
+	return "SubscriptionLag"
+}
+
+func (SubscriptionLag) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (SubscriptionLag) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx.Operation == nil || opCtx.Operation.Operation != ast.Subscription {
+		return next(ctx)
+	}
+
+	lag := &pubsub.Lag{}
+	responses := next(pubsub.WithLag(ctx, lag))
+
+	var pending *graphql.Response
+	var done bool
+	return func(ctx context.Context) *graphql.Response {
+		if pending != nil {
+			resp := pending
+			pending = nil
+			return resp
+		}
+		if done {
+			return nil
+		}
+
+		resp := responses(ctx)
+		if resp == nil {
+			done = true
+			if lag.Disconnected() {
+				return laggedError(lag.TakeSkipped())
+			}
+			return nil
+		}
+		// Events buffered before a disconnect still arrive in order, and the
+		// error at the end accounts for what was skipped.
+		if lag.Disconnected() {
+			return resp
+		}
+		if skipped := lag.TakeSkipped(); skipped > 0 {
+			pending = resp
+			return laggedResponse(skipped)
+		}
+		return resp
+	}
+}
+
+func laggedResponse(skipped uint64) *graphql.Response {
+	return &graphql.Response{Extensions: map[string]any{
+		subscriptionLagged: map[string]any{"skipped": skipped},
+	}}
+}
+
+func laggedError(skipped uint64) *graphql.Response {
+	return &graphql.Response{Errors: gqlerror.List{{
+		Message: fmt.Sprintf("subscriber fell behind after %d skipped events, please resubscribe", skipped),
+		Extensions: map[string]any{
+			"code":    CodeSubscriptionLagged,
+			"skipped": skipped,
+		},
+	}}}
+}
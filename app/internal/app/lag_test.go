@@ -0,0 +1,83 @@
+package app
+
+import (
+	"app/graph/model"
+	"app/internal/pubsub"
+	mock_pubsub "app/internal/pubsub/mocks"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionLag(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   pubsub.OverflowPolicy
+		expected []string
+	}{
+		{
+			name:   "drop oldest",
+			policy: pubsub.DropOldest,
+			expected: []string{
+				`{"data":null,"extensions":{"subscriptionLagged":{"skipped":2}}}`,
+				`{"data":{"commentAdded":{"id":"comment-3"}}}`,
+				`{"data":{"commentAdded":{"id":"comment-4"}}}`,
+			},
+		},
+		{
+			name:   "disconnect",
+			policy: pubsub.Disconnect,
+			expected: []string{
+				`{"data":{"commentAdded":{"id":"comment-1"}}}`,
+				`{"data":{"commentAdded":{"id":"comment-2"}}}`,
+				`{"errors":[{"message":"subscriber fell behind after 1 skipped events, please resubscribe","extensions":{"code":"SUBSCRIPTION_LAGGED","skipped":1}}],"data":null}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			postID := uuid.New()
+			ps := mock_pubsub.NewMockPubSubClient(ctrl)
+			// Four comments arrive before the client reads any of them.
+			ps.EXPECT().SubscribeOnComments(gomock.Any(), postID).DoAndReturn(func(ctx context.Context, postID uuid.UUID) (<-chan *model.Comment, error) {
+				comments := make(chan *model.Comment, 2)
+				for i := 1; i <= 4; i++ {
+					comment := &model.Comment{ID: "comment-" + strconv.Itoa(i)}
+					if _, ok := pubsub.Send(ctx, comments, comment, tt.policy); !ok {
+						close(comments)
+						return comments, nil
+					}
+				}
+				return comments, nil
+			})
+
+			server := httptest.NewServer(newTestServer(t, ps).routes())
+			defer server.Close()
+
+			conn := dialWebsocket(t, server.URL+"/query", graphqlTransportWS)
+			defer conn.Close()
+
+			require.NoError(t, conn.WriteJSON(wsMessage{Type: "connection_init"}))
+			readWebsocket(t, conn, "connection_ack")
+
+			start, err := json.Marshal(map[string]any{"query": commentAddedQuery, "variables": variables(postID)})
+			require.NoError(t, err)
+			require.NoError(t, conn.WriteJSON(wsMessage{Type: "subscribe", ID: "1", Payload: start}))
+
+			for _, expected := range tt.expected {
+				assert.JSONEq(t, expected, string(readWebsocket(t, conn, "next").Payload))
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"app/graph"
+	"app/graph/model"
+	"app/internal/service"
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// newComplexityRoot weighs list fields by the number of items they can
+// return, so that nesting paginated fields multiplies the cost. Page sizes
+// are capped at maxPageSize as they are in the resolvers.
+func newComplexityRoot(maxPageSize int32, maxCommentDepth int) graph.ComplexityRoot {
+	page := func(childComplexity int, n int32) int {
+		if n < 0 {
+			n = 0
+		}
+		if maxPageSize > 0 && n > maxPageSize {
+			n = maxPageSize
+		}
+		return 1 + childComplexity*int(n)
+	}
+	attachments := func(childComplexity int) int {
+		return 1 + childComplexity*service.MaxAttachmentsPerItem
+	}
+
+	var root graph.ComplexityRoot
+
+	root.Query.Posts = func(childComplexity int, limit int32, offset int32, sortBy *model.SortBy, viewerID *string) int {
+		return page(childComplexity, limit)
+	}
+	root.Query.Replies = func(childComplexity int, commentID string, limit int32, offset int32) int {
+		return page(childComplexity, limit)
+	}
+	root.User.Posts = func(childComplexity int, first int32, after *string, viewerID *string) int {
+		return page(childComplexity, first)
+	}
+	root.User.Comments = func(childComplexity int, first int32, after *string) int {
+		return page(childComplexity, first)
+	}
+	root.Post.Comments = func(childComplexity int, limit int32, offset int32) int {
+		return page(childComplexity, limit)
+	}
+	root.Post.Revisions = func(childComplexity int, first int32, after *string) int {
+		return page(childComplexity, first)
+	}
+	root.Post.Attachments = attachments
+	root.Comment.Replies = func(childComplexity int, limit int32, offset int32) int {
+		return page(childComplexity, limit)
+	}
+	root.Comment.Attachments = attachments
+	if maxCommentDepth > 0 {
+		root.Comment.Ancestors = func(childComplexity int) int {
+			return 1 + childComplexity*maxCommentDepth
+		}
+	}
+
+	return root
+}
+
+// DepthLimit rejects operations whose selections nest deeper than Max.
+// Introspection fields are not counted, so that tools can still load the
+// schema.
+type DepthLimit struct {
+	Max int
+}
+
+var (
+	_ graphql.HandlerExtension        = DepthLimit{}
+	_ graphql.OperationContextMutator = DepthLimit{}
+)
+
+func (d DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+
+	if depth := selectionDepth(opCtx.Operation.SelectionSet); depth > d.Max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Max)
+		errcode.Set(err, CodeDepthLimit)
+		return err
+	}
+	return nil
+}
+
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range set {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
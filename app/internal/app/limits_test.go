@@ -0,0 +1,65 @@
+package app
+
+import (
+	"app/graph"
+	"testing"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func parseOperation(t *testing.T, es interface{ Schema() *ast.Schema }, query string) *ast.OperationDefinition {
+	doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+	require.Nil(t, errs)
+	require.Len(t, doc.Operations, 1)
+	return doc.Operations[0]
+}
+
+func TestComplexity(t *testing.T) {
+	es := graph.NewExecutableSchema(graph.Config{Complexity: newComplexityRoot(100, 10)})
+
+	tests := []struct {
+		name       string
+		query      string
+		complexity int
+	}{
+		{"scalar fields", `{ post(id: "1") { id title } }`, 3},
+		{"page multiplies children", `{ posts(limit: 20, offset: 0) { id title } }`, 1 + 20*2},
+		{"nested pages multiply", `{ posts(limit: 10, offset: 0) { comments(limit: 5, offset: 0) { id } } }`, 1 + 10*(1+5*1)},
+		{"page size is capped", `{ posts(limit: 100000, offset: 0) { id } }`, 1 + 100*1},
+		{"attachments", `{ post(id: "1") { attachments { id } } }`, 1 + 1 + 10*1},
+		{"ancestors", `{ replies(commentId: "1", limit: 1, offset: 0) { ancestors { id } } }`, 1 + 1*(1+10*1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := parseOperation(t, es, tt.query)
+			assert.Equal(t, tt.complexity, complexity.Calculate(es, op, nil))
+		})
+	}
+}
+
+func TestSelectionDepth(t *testing.T) {
+	es := graph.NewExecutableSchema(graph.Config{})
+
+	tests := []struct {
+		name  string
+		query string
+		depth int
+	}{
+		{"flat", `{ post(id: "1") { id } }`, 2},
+		{"nested", `{ posts(limit: 1, offset: 0) { comments(limit: 1, offset: 0) { replies(limit: 1, offset: 0) { user { username } } } } }`, 5},
+		{"fragments", `query { post(id: "1") { ...P } } fragment P on Post { comments(limit: 1, offset: 0) { ... on Comment { user { id } } } }`, 4},
+		{"introspection is ignored", `{ __schema { types { fields { type { ofType { name } } } } } post(id: "1") { id } }`, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := parseOperation(t, es, tt.query)
+			assert.Equal(t, tt.depth, selectionDepth(op.SelectionSet))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"app/internal/repository"
+	"context"
+	"fmt"
+	"io"
+)
+
+// ReportCrossPostReplies writes every reply whose parent lives on another
+// post and returns how many were found. Nothing is modified: such replies
+// predate the same-post constraint and have to be fixed by hand.
+func ReportCrossPostReplies(ctx context.Context, comments repository.CommentRepo, w io.Writer) (int, error) {
+	replies, err := comments.GetCrossPostReplies(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find cross-post replies: %w", err)
+	}
+
+	for _, reply := range replies {
+		parent, err := comments.GetOneById(ctx, *reply.ParentId)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get parent of comment %s: %w", reply.Id, err)
+		}
+		fmt.Fprintf(w, "comment %s on post %s replies to comment %s on post %s\n",
+			reply.Id, reply.PostId, parent.Id, parent.PostId)
+	}
+
+	return len(replies), nil
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"app/internal/entity"
+	mock_repository "app/internal/repository/mocks"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportCrossPostReplies(t *testing.T) {
+	ctx := context.Background()
+	parent := entity.Comment{Id: uuid.New(), PostId: uuid.New()}
+	reply := entity.Comment{Id: uuid.New(), PostId: uuid.New(), ParentId: &parent.Id}
+
+	t.Run("reports cross-post replies", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		comments := mock_repository.NewMockCommentRepo(ctrl)
+		comments.EXPECT().GetCrossPostReplies(ctx).Return([]entity.Comment{reply}, nil)
+		comments.EXPECT().GetOneById(ctx, parent.Id).Return(&parent, nil)
+
+		var out strings.Builder
+		found, err := ReportCrossPostReplies(ctx, comments, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, found)
+		assert.Equal(t, fmt.Sprintf("comment %s on post %s replies to comment %s on post %s\n",
+			reply.Id, reply.PostId, parent.Id, parent.PostId), out.String())
+	})
+
+	t.Run("nothing to report", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		comments := mock_repository.NewMockCommentRepo(ctrl)
+		comments.EXPECT().GetCrossPostReplies(ctx).Return([]entity.Comment{}, nil)
+
+		var out strings.Builder
+		found, err := ReportCrossPostReplies(ctx, comments, &out)
+		assert.NoError(t, err)
+		assert.Zero(t, found)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		comments := mock_repository.NewMockCommentRepo(ctrl)
+		dbErr := errors.New("db error")
+		comments.EXPECT().GetCrossPostReplies(ctx).Return(nil, dbErr)
+
+		_, err := ReportCrossPostReplies(ctx, comments, &strings.Builder{})
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
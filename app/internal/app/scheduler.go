@@ -0,0 +1,103 @@
+package app
+
+import (
+	"app/internal/pubsub"
+	"app/internal/service"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Scheduler periodically publishes scheduled posts whose publish time has
+// passed. Flipping the status is a single conditional update in the
+// repository, so several instances can run it without double-publishing.
+type Scheduler struct {
+	posts    service.Post
+	pubsub   pubsub.PubSubClient
+	interval time.Duration
+	logger   *slog.Logger
+	stop     chan struct{}
+	done     chan struct{}
+
+	mu      sync.Mutex
+	running bool
+	stopped bool
+}
+
+func NewScheduler(posts service.Post, pubsub pubsub.PubSubClient, interval time.Duration, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		posts:    posts,
+		pubsub:   pubsub,
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run blocks until Stop is called. It returns at once if the scheduler was
+// already stopped or is running elsewhere.
+func (s *Scheduler) Run() {
+	s.mu.Lock()
+	if s.running || s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Scheduler started", "interval", s.interval)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.publishDue(now)
+		}
+	}
+}
+
+// Stop waits for Run to return. It is safe to call before Run and more than
+// once.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	running := s.running
+	close(s.stop)
+	s.mu.Unlock()
+
+	if running {
+		<-s.done
+	}
+}
+
+func (s *Scheduler) publishDue(now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+	defer cancel()
+
+	posts, err := s.posts.PublishDuePosts(ctx, now)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to publish scheduled posts", "error", err)
+		return
+	}
+
+	for _, post := range posts {
+		if err := s.pubsub.PublishPost(ctx, post); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to notify about published post", "post_id", post.ID, "error", err)
+		}
+	}
+
+	if len(posts) > 0 {
+		s.logger.InfoContext(ctx, "Published scheduled posts", "count", len(posts))
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerStop(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("before run", func(t *testing.T) {
+		scheduler := NewScheduler(nil, nil, time.Second, logger)
+
+		stopped := make(chan struct{})
+		go func() {
+			scheduler.Stop()
+			scheduler.Stop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("Stop blocked although Run was never called")
+		}
+
+		ran := make(chan struct{})
+		go func() {
+			scheduler.Run()
+			close(ran)
+		}()
+
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatal("Run did not return after Stop")
+		}
+	})
+
+	t.Run("while running", func(t *testing.T) {
+		scheduler := NewScheduler(nil, nil, time.Hour, logger)
+
+		ran := make(chan struct{})
+		go func() {
+			scheduler.Run()
+			close(ran)
+		}()
+
+		assert.Eventually(t, func() bool {
+			scheduler.mu.Lock()
+			defer scheduler.mu.Unlock()
+			return scheduler.running
+		}, time.Second, 10*time.Millisecond)
+
+		scheduler.Stop()
+		<-ran
+	})
+}
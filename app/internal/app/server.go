@@ -3,12 +3,24 @@ package app
 import (
 	"app/graph"
 	"app/graph/resolver"
+	"app/internal/blobstore"
 	"app/internal/config"
+	"app/internal/cors"
+	"app/internal/health"
+	"app/internal/logging"
+	"app/internal/metrics"
+	"app/internal/ratelimit"
+	"app/internal/tracing"
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
@@ -18,68 +30,170 @@ import (
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
+const mediaPath = "/media/"
+
+// Websocket sub-protocols in order of preference: graphql-transport-ws is the
+// current protocol, graphql-ws the legacy one of subscriptions-transport-ws.
+const (
+	graphqlTransportWS = "graphql-transport-ws"
+	graphqlWS          = "graphql-ws"
+)
+
+const (
+	keepAliveInterval = 10 * time.Second
+	sseContentType    = "text/event-stream"
+)
+
+// Uploads above maxUploadMemory are spooled to temporary files; the request
+// size leaves room for the largest attachment plus the rest of the form.
+const (
+	maxUploadSize   int64 = 12 << 20
+	maxUploadMemory int64 = 4 << 20
+)
+
 type Server struct {
-	handler http.Handler
-	server  *http.Server
-	cfg     *config.Config
+	handler        http.Handler
+	mediaHandler   http.Handler
+	metricsHandler http.Handler
+	health         *health.Health
+	drainer        *Drainer
+	server         *http.Server
+	cors           cors.Policy
+	cfg            *config.Config
+	logger         *slog.Logger
 }
 
-func NewServer(cfg *config.Config, resolver *resolver.Resolver) *Server {
+func NewServer(cfg *config.Config, resolver *resolver.Resolver, blobStore blobstore.BlobStore, logger *slog.Logger, m *metrics.Metrics, h *health.Health, limiter *ratelimit.Limiter, persistedQueries graphql.HandlerExtension) *Server {
+	limits := cfg.QueryLimitsConfig
+	corsPolicy := cors.Policy{
+		AllowedOrigins: cfg.CORSConfig.AllowedOrigins,
+		AllowedMethods: cfg.CORSConfig.AllowedMethods,
+		AllowedHeaders: cfg.CORSConfig.AllowedHeaders,
+		MaxAge:         cfg.CORSConfig.MaxAge,
+
+		TrustForwardedProto: cfg.TrustedProxies > 0,
+	}
 	srv := handler.New(graph.NewExecutableSchema(graph.Config{
-		Resolvers: resolver,
+		Resolvers:  resolver,
+		Complexity: newComplexityRoot(limits.MaxPageSize, cfg.CommentConfig.MaxDepth),
 	}))
 
-	configureTransports(srv)
+	drainer := NewDrainer()
+	configureTransports(srv, corsPolicy, drainer)
+	srv.Use(drainer)
+	srv.Use(SubscriptionLag{})
+	srv.Use(persistedQueries)
+	srv.Use(Introspection{Enabled: cfg.Profile().Introspection, AdminToken: cfg.AdminToken})
+	srv.Use(extension.FixedComplexityLimit(limits.MaxComplexity))
+	srv.Use(DepthLimit{Max: limits.MaxDepth})
+	srv.SetErrorPresenter(errorPresenter(cfg.Profile().VerboseErrors))
+	srv.SetRecoverFunc(recoverPanic)
+	srv.Use(tracing.Extension{})
+	srv.Use(logging.Extension{Logger: logger})
+	srv.Use(m.Extension())
+	if limiter != nil {
+		srv.Use(limiter)
+	}
 
 	return &Server{
-		handler: srv,
-		cfg:     cfg,
+		handler:        srv,
+		mediaHandler:   http.StripPrefix(mediaPath, blobstore.NewHandler(blobStore, logger)),
+		metricsHandler: m.Handler(),
+		health:         h,
+		drainer:        drainer,
+		cors:           corsPolicy,
+		cfg:            cfg,
+		logger:         logger,
 	}
 }
 
 func (s *Server) Run() {
-	mux := http.NewServeMux()
-	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	mux.Handle("/query", s.handler)
-
 	s.server = &http.Server{
 		Addr:         ":" + s.cfg.Port,
-		Handler:      mux,
+		Handler:      s.routes(),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
-	log.Printf("Server started")
+	s.logger.Info("Server started", "addr", s.server.Addr)
 	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("%v", err)
+		s.logger.Error("Server failed", "error", err)
+		os.Exit(1)
 	}
 }
 
-func (s *Server) Stop() {
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	if s.cfg.Profile().Playground {
+		mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	}
+	query := ratelimit.ClientMiddleware(streamingMiddleware(s.handler), s.cfg.TrustedProxies)
+	mux.Handle("/query", logging.RequestIDMiddleware(query))
+	mux.Handle(mediaPath, s.mediaHandler)
+	mux.Handle("/metrics", s.metricsHandler)
+	mux.Handle("/healthz", s.health.LivenessHandler())
+	mux.Handle("/readyz", s.health.ReadinessHandler())
+	return s.cors.Middleware(mux)
+}
+
+// Stop stops accepting connections, waits for running requests and then
+// drains subscriptions and websocket connections, which Shutdown of
+// http.Server leaves alone once they are hijacked.
+//
+// Event streams are ordinary requests that Shutdown waits for, so draining
+// runs alongside it rather than after it.
+func (s *Server) Stop(ctx context.Context) error {
+	drained := make(chan error, 1)
+	go func() {
+		drained <- s.drainer.Shutdown(ctx)
+	}()
+
+	var errs []error
 	if s.server != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		s.server.Shutdown(ctx)
+		if err := s.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop http server: %w", err))
+		}
+	}
+	if err := <-drained; err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain subscriptions: %w", err))
 	}
+	return errors.Join(errs...)
 }
 
-func configureTransports(srv *handler.Server) {
+// streamingMiddleware lifts the write timeout for event streams, which stay
+// open for as long as their subscription runs.
+func streamingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), sseContentType) {
+			http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Subscriptions are served over websockets with either sub-protocol, and over
+// server-sent events for clients behind proxies that break websockets. Both
+// send a keepalive every keepAliveInterval.
+func configureTransports(srv *handler.Server, corsPolicy cors.Policy, drainer *Drainer) {
 	srv.AddTransport(transport.Websocket{
-		KeepAlivePingInterval: 10 * time.Second,
+		KeepAlivePingInterval: keepAliveInterval,
+		PingPongInterval:      keepAliveInterval,
+		InitFunc:              drainer.WebsocketInit(ratelimit.WebsocketInit),
+		CloseFunc:             drainer.WebsocketClose,
 		Upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true
-			},
+			CheckOrigin:  corsPolicy.CheckOrigin,
+			Subprotocols: []string{graphqlTransportWS, graphqlWS},
 		},
 	})
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
+	// SSE requests are POSTs as well and have to be matched first.
+	srv.AddTransport(transport.SSE{KeepAlivePingInterval: keepAliveInterval})
 	srv.AddTransport(transport.POST{})
-	srv.AddTransport(transport.MultipartForm{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: maxUploadSize,
+		MaxMemory:     maxUploadMemory,
+	})
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
-	srv.Use(extension.Introspection{})
-	srv.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New[string](100),
-	})
 }
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// shutdownReason is sent to websocket clients before their connection is
+// closed on shutdown.
+const shutdownReason = "server is shutting down, please reconnect"
+
+var errShuttingDown = errors.New(shutdownReason)
+
+type connectionIDKey struct{}
+
+// Drainer keeps track of running subscriptions and websocket connections,
+// which http.Server.Shutdown neither waits for nor closes. On Shutdown every
+// subscription gets a final error carrying a reconnect hint followed by
+// complete, and then every connection is closed with the same reason.
+type Drainer struct {
+	mu       sync.Mutex
+	draining bool
+	nextID   uint64
+
+	subscriptions map[uint64]context.CancelCauseFunc
+	connections   map[uint64]context.CancelFunc
+
+	// released is closed, and replaced, whenever a subscription or a
+	// connection goes away.
+	released chan struct{}
+}
+
+func NewDrainer() *Drainer {
+	return &Drainer{
+		subscriptions: make(map[uint64]context.CancelCauseFunc),
+		connections:   make(map[uint64]context.CancelFunc),
+		released:      make(chan struct{}),
+	}
+}
+
+var (
+	_ graphql.HandlerExtension     = &Drainer{}
+	_ graphql.OperationInterceptor = &Drainer{}
+)
+
+func (d *Drainer) ExtensionName() string {
+	return "Drainer"
+}
+
+func (d *Drainer) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d *Drainer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx.Operation == nil || opCtx.Operation.Operation != ast.Subscription {
+		return next(ctx)
+	}
+
+	subCtx, cancel := context.WithCancelCause(ctx)
+	if !d.trackSubscription(ctx, cancel) {
+		cancel(errShuttingDown)
+		return graphql.OneShot(shutdownResponse())
+	}
+
+	responses := next(subCtx)
+	var notified bool
+	return func(ctx context.Context) *graphql.Response {
+		if resp := responses(ctx); resp != nil {
+			return resp
+		}
+		// The stream ended because of Shutdown: tell the client why before
+		// the transport sends complete.
+		if !notified && errors.Is(context.Cause(subCtx), errShuttingDown) {
+			notified = true
+			return shutdownResponse()
+		}
+		return nil
+	}
+}
+
+// trackSubscription registers a subscription until the transport is done
+// with its operation, which for websockets happens after complete is sent.
+func (d *Drainer) trackSubscription(ctx context.Context, cancel context.CancelCauseFunc) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return false
+	}
+
+	id := d.nextID
+	d.nextID++
+	d.subscriptions[id] = cancel
+
+	context.AfterFunc(ctx, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.subscriptions, id)
+		d.release()
+	})
+	return true
+}
+
+// WebsocketInit wraps init so that connections opened through it are closed
+// on Shutdown, and refuses new connections once draining has started.
+func (d *Drainer) WebsocketInit(init transport.WebsocketInitFunc) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		ctx, ack, err := init(ctx, payload)
+		if err != nil {
+			return ctx, ack, err
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		if d.draining {
+			return ctx, nil, errShuttingDown
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		id := d.nextID
+		d.nextID++
+		d.connections[id] = cancel
+
+		ctx = transport.AppendCloseReason(ctx, shutdownReason)
+		return context.WithValue(ctx, connectionIDKey{}, id), ack, nil
+	}
+}
+
+// WebsocketClose forgets the connection once the transport has closed it.
+func (d *Drainer) WebsocketClose(ctx context.Context, closeCode int) {
+	id, ok := ctx.Value(connectionIDKey{}).(uint64)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if cancel, tracked := d.connections[id]; tracked {
+		cancel()
+		delete(d.connections, id)
+		d.release()
+	}
+}
+
+// release wakes up Shutdown. d.mu must be held.
+func (d *Drainer) release() {
+	close(d.released)
+	d.released = make(chan struct{})
+}
+
+// Shutdown completes all subscriptions, then closes all websocket
+// connections, waiting for each step until ctx is done.
+func (d *Drainer) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	for _, cancel := range d.subscriptions {
+		cancel(errShuttingDown)
+	}
+	d.mu.Unlock()
+
+	if err := wait(ctx, d, d.subscriptions); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	for _, cancel := range d.connections {
+		cancel()
+	}
+	d.mu.Unlock()
+
+	return wait(ctx, d, d.connections)
+}
+
+// wait returns once tracked is empty or ctx is done, whichever comes first.
+func wait[F any](ctx context.Context, d *Drainer, tracked map[uint64]F) error {
+	for {
+		d.mu.Lock()
+		remaining, released := len(tracked), d.released
+		d.mu.Unlock()
+
+		if remaining == 0 {
+			return nil
+		}
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func shutdownResponse() *graphql.Response {
+	return &graphql.Response{Errors: gqlerror.List{{
+		Message: shutdownReason,
+		Extensions: map[string]any{
+			"code":      CodeShuttingDown,
+			"reconnect": true,
+		},
+	}}}
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"app/graph"
+	"app/graph/model"
+	"app/graph/resolver"
+	"app/internal/cors"
+	mock_pubsub "app/internal/pubsub/mocks"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wsMessage struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func TestDrainerShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subscribed := make(chan struct{})
+	pubsub := mock_pubsub.NewMockPubSubClient(ctrl)
+	pubsub.EXPECT().SubscribeOnPosts(gomock.Any()).DoAndReturn(func(ctx context.Context) (<-chan *model.Post, error) {
+		posts := make(chan *model.Post)
+		go func() {
+			<-ctx.Done()
+			close(posts)
+		}()
+		close(subscribed)
+		return posts, nil
+	})
+
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver.Resolver{PubSubClient: pubsub}}))
+	drainer := NewDrainer()
+	configureTransports(srv, cors.Policy{}, drainer)
+	srv.Use(drainer)
+
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	baseline := runtime.NumGoroutine()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "connection_init"}))
+	var ack wsMessage
+	require.NoError(t, conn.ReadJSON(&ack))
+	require.Equal(t, "connection_ack", ack.Type)
+
+	require.NoError(t, conn.WriteJSON(wsMessage{
+		Type:    "start",
+		ID:      "1",
+		Payload: json.RawMessage(`{"query":"subscription { postPublished { id } }"}`),
+	}))
+	select {
+	case <-subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, drainer.Shutdown(ctx))
+
+	var received []wsMessage
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		if msg.Type != "ka" {
+			received = append(received, msg)
+		}
+	}
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "data", received[0].Type)
+	assert.Contains(t, string(received[0].Payload), `"code":"SHUTTING_DOWN"`)
+	assert.Contains(t, string(received[0].Payload), `"reconnect":true`)
+	assert.Equal(t, wsMessage{Type: "complete", ID: "1"}, received[1])
+	assert.Equal(t, "connection_error", received[2].Type)
+	assert.Contains(t, string(received[2].Payload), shutdownReason)
+
+	conn.Close()
+	assertNoGoroutineLeak(t, baseline)
+}
+
+// assertNoGoroutineLeak waits for the number of goroutines to drop back to
+// baseline. It polls by hand, since assert.Eventually runs the condition in
+// goroutines of its own.
+func assertNoGoroutineLeak(t *testing.T, baseline int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("goroutines leaked: %d running, %d before\n%s", runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDrainerShutdownTimeout(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	drainer := NewDrainer()
+
+	// A subscription that ignores the cancellation keeps Shutdown waiting.
+	subCtx, endSubscription := context.WithCancel(context.Background())
+	defer endSubscription()
+	require.True(t, drainer.trackSubscription(subCtx, func(error) {}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, drainer.Shutdown(ctx), context.DeadlineExceeded)
+
+	endSubscription()
+	assertNoGoroutineLeak(t, baseline)
+}
+
+func TestServerStopWaitsForDrain(t *testing.T) {
+	drainer := NewDrainer()
+	subCtx, endSubscription := context.WithCancel(context.Background())
+	defer endSubscription()
+	require.True(t, drainer.trackSubscription(subCtx, func(error) {}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := (&Server{server: &http.Server{}, drainer: drainer}).Stop(ctx)
+
+	// Shutdown of the idle http.Server succeeds; the drain does not.
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "failed to drain subscriptions")
+}
+
+func TestDrainerRefusesConnectionsWhileDraining(t *testing.T) {
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver.Resolver{}}))
+	drainer := NewDrainer()
+	configureTransports(srv, cors.Policy{}, drainer)
+	srv.Use(drainer)
+
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	require.NoError(t, drainer.Shutdown(context.Background()))
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "connection_init"}))
+	var msg wsMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "connection_error", msg.Type)
+	assert.Contains(t, string(msg.Payload), shutdownReason)
+}
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"app/graph/model"
+	"app/graph/resolver"
+	blobstore_local "app/internal/blobstore/local"
+	"app/internal/config"
+	"app/internal/health"
+	"app/internal/metrics"
+	mock_pubsub "app/internal/pubsub/mocks"
+	"app/internal/ratelimit"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commentAddedQuery = `subscription($postId: ID!) { commentAdded(postId: $postId) { id } }`
+
+type commentAddedPayload struct {
+	Data struct {
+		CommentAdded struct {
+			ID string `json:"id"`
+		} `json:"commentAdded"`
+	} `json:"data"`
+}
+
+func TestCommentAddedTransports(t *testing.T) {
+	tests := []struct {
+		name      string
+		subscribe func(t *testing.T, url string, postID uuid.UUID) commentAddedPayload
+	}{
+		{"graphql-transport-ws", subscribeTransportWS},
+		{"graphql-ws", subscribeLegacyWS},
+		{"sse", subscribeSSE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			postID := uuid.New()
+			userIDs := make(chan string, 1)
+			pubsub := mock_pubsub.NewMockPubSubClient(ctrl)
+			pubsub.EXPECT().SubscribeOnComments(gomock.Any(), postID).DoAndReturn(func(ctx context.Context, postID uuid.UUID) (<-chan *model.Comment, error) {
+				client, _ := ratelimit.ClientFromContext(ctx)
+				userIDs <- client.UserID
+
+				comments := make(chan *model.Comment, 1)
+				comments <- &model.Comment{ID: "comment-1"}
+				go func() {
+					<-ctx.Done()
+					close(comments)
+				}()
+				return comments, nil
+			})
+
+			server := httptest.NewServer(newTestServer(t, pubsub).routes())
+			defer server.Close()
+
+			payload := tt.subscribe(t, server.URL+"/query", postID)
+			assert.Equal(t, "comment-1", payload.Data.CommentAdded.ID)
+			assert.Equal(t, "user-1", <-userIDs)
+		})
+	}
+}
+
+func newTestServer(t *testing.T, pubsub *mock_pubsub.MockPubSubClient) *Server {
+	cfg := &config.Config{
+		QueryLimitsConfig: config.QueryLimitsConfig{MaxComplexity: 5000, MaxDepth: 10, MaxPageSize: 100},
+		CommentConfig:     config.CommentConfig{MaxDepth: 10},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	blobStore, err := blobstore_local.NewLocalBlobStore(t.TempDir(), mediaPath)
+	require.NoError(t, err)
+
+	resolver := &resolver.Resolver{PubSubClient: pubsub, Logger: logger, MaxPageSize: 100}
+	apq := extension.AutomaticPersistedQuery{Cache: lru.New[string](apqCacheSize)}
+	return NewServer(cfg, resolver, blobStore, logger, metrics.New(), health.New(logger), nil, apq)
+}
+
+func variables(postID uuid.UUID) map[string]any {
+	return map[string]any{"postId": postID.String()}
+}
+
+// dialWebsocket authenticates with a header on the upgrade request, as
+// clients outside the browser do.
+func dialWebsocket(t *testing.T, url string, subprotocol string) *websocket.Conn {
+	header := http.Header{}
+	header.Set(ratelimit.UserIDHeader, "user-1")
+
+	dialer := websocket.Dialer{Subprotocols: []string{subprotocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(url, "http"), header)
+	require.NoError(t, err)
+	require.Equal(t, subprotocol, conn.Subprotocol())
+	return conn
+}
+
+// readWebsocket returns the next message of type messageType, skipping
+// keepalives.
+func readWebsocket(t *testing.T, conn *websocket.Conn, messageType string) wsMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var msg wsMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		if msg.Type == "ka" || msg.Type == "ping" {
+			continue
+		}
+		require.Equal(t, messageType, msg.Type, string(msg.Payload))
+		return msg
+	}
+}
+
+func subscribeTransportWS(t *testing.T, url string, postID uuid.UUID) commentAddedPayload {
+	conn := dialWebsocket(t, url, graphqlTransportWS)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "connection_init"}))
+	readWebsocket(t, conn, "connection_ack")
+
+	start, err := json.Marshal(map[string]any{"query": commentAddedQuery, "variables": variables(postID)})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "subscribe", ID: "1", Payload: start}))
+
+	var payload commentAddedPayload
+	require.NoError(t, json.Unmarshal(readWebsocket(t, conn, "next").Payload, &payload))
+	return payload
+}
+
+func subscribeLegacyWS(t *testing.T, url string, postID uuid.UUID) commentAddedPayload {
+	conn := dialWebsocket(t, url, graphqlWS)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "connection_init"}))
+	readWebsocket(t, conn, "connection_ack")
+
+	start, err := json.Marshal(map[string]any{"query": commentAddedQuery, "variables": variables(postID)})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "start", ID: "1", Payload: start}))
+
+	var payload commentAddedPayload
+	require.NoError(t, json.Unmarshal(readWebsocket(t, conn, "data").Payload, &payload))
+	return payload
+}
+
+func subscribeSSE(t *testing.T, url string, postID uuid.UUID) commentAddedPayload {
+	body, err := json.Marshal(map[string]any{"query": commentAddedQuery, "variables": variables(postID)})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", sseContentType)
+	req.Header.Set(ratelimit.UserIDHeader, "user-1")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, sseContentType, resp.Header.Get("Content-Type"))
+
+	// Events are "event: next" followed by a data line; lines starting with
+	// a colon are keepalive comments.
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "next":
+			var payload commentAddedPayload
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &payload))
+			return payload
+		}
+	}
+	t.Fatalf("stream ended without an event: %v", scanner.Err())
+	return commentAddedPayload{}
+}
@@ -0,0 +1,29 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("Blob not found")
+	ErrInvalidKey = errors.New("Invalid blob key")
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=blobstore.go -destination=mocks/blobstore.go
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+type Object struct {
+	Content     io.ReadCloser
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
@@ -0,0 +1,49 @@
+package blobstore
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func NewHandler(store BlobStore, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		object, err := store.Get(r.Context(), key)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrNotFound), errors.Is(err, ErrInvalidKey):
+				http.NotFound(w, r)
+			default:
+				logger.ErrorContext(r.Context(), "Failed to read blob", "key", key, "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		defer object.Content.Close()
+
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		if !object.ModTime.IsZero() {
+			w.Header().Set("Last-Modified", object.ModTime.UTC().Format(http.TimeFormat))
+		}
+
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := io.Copy(w, object.Content); err != nil {
+			logger.WarnContext(r.Context(), "Failed to send blob", "key", key, "error", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package blobstore_local
+
+import (
+	"app/internal/blobstore"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) *LocalBlobStore {
+		store, err := NewLocalBlobStore(t.TempDir(), "/media")
+		require.NoError(t, err)
+		return store
+	}
+
+	t.Run("Put/Get", func(t *testing.T) {
+		store := setup(t)
+
+		err := store.Put(ctx, "avatars/user/avatar.png", strings.NewReader("image"), "image/png")
+		require.NoError(t, err)
+
+		object, err := store.Get(ctx, "avatars/user/avatar.png")
+		require.NoError(t, err)
+		defer object.Content.Close()
+
+		content, err := io.ReadAll(object.Content)
+		require.NoError(t, err)
+		assert.Equal(t, "image", string(content))
+		assert.Equal(t, "image/png", object.ContentType)
+		assert.Equal(t, int64(5), object.Size)
+	})
+
+	t.Run("Get/not found", func(t *testing.T) {
+		store := setup(t)
+
+		_, err := store.Get(ctx, "avatars/missing.png")
+		assert.ErrorIs(t, err, blobstore.ErrNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := setup(t)
+		require.NoError(t, store.Put(ctx, "avatar.png", strings.NewReader("image"), "image/png"))
+
+		assert.NoError(t, store.Delete(ctx, "avatar.png"))
+		assert.ErrorIs(t, store.Delete(ctx, "avatar.png"), blobstore.ErrNotFound)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		store := setup(t)
+
+		for _, key := range []string{"", "../escape.png", "/absolute.png", "a/../../b.png", `a\b.png`} {
+			err := store.Put(ctx, key, strings.NewReader("x"), "image/png")
+			assert.ErrorIs(t, err, blobstore.ErrInvalidKey, key)
+		}
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		store := setup(t)
+		assert.Equal(t, "/media/avatars/a.png", store.URL("avatars/a.png"))
+	})
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -13,6 +14,30 @@ const (
 	postgres databaseType = "postgres"
 )
 
+type environment string
+
+const (
+	envDev     environment = "dev"
+	envStaging environment = "staging"
+	envProd    environment = "prod"
+)
+
+// Profile holds the settings that follow from the environment.
+type Profile struct {
+	Playground    bool
+	Introspection bool
+	// VerboseErrors sends the text of unexpected errors to clients instead
+	// of masking it.
+	VerboseErrors bool
+	LogLevel      string
+}
+
+var profiles = map[environment]Profile{
+	envDev:     {Playground: true, Introspection: true, VerboseErrors: true, LogLevel: "debug"},
+	envStaging: {Playground: true, Introspection: true, VerboseErrors: false, LogLevel: "info"},
+	envProd:    {Playground: false, Introspection: false, VerboseErrors: false, LogLevel: "info"},
+}
+
 type DatabaseConfig interface {
 	DSN() string
 }
@@ -24,6 +49,12 @@ type PostgresConfig struct {
 	Password string `env:"POSTGRES_PASSWORD"`
 	DBName   string `env:"POSTGRES_DB"`
 	SSLMode  string `env:"POSTGRES_SSLMODE" env-default:"disable"`
+
+	RetryAttempts int           `env:"POSTGRES_RETRY_ATTEMPTS" env-default:"3"`
+	RetryDelay    time.Duration `env:"POSTGRES_RETRY_DELAY" env-default:"50ms"`
+
+	ConnectRetryDelay    time.Duration `env:"POSTGRES_CONNECT_RETRY_DELAY" env-default:"1s"`
+	ConnectRetryMaxDelay time.Duration `env:"POSTGRES_CONNECT_RETRY_MAX_DELAY" env-default:"30s"`
 }
 
 func (c PostgresConfig) DSN() string {
@@ -44,11 +75,113 @@ type RedisConfig struct {
 	DB       int    `env:"REDIS_DB"`
 }
 
+type BlobStoreConfig struct {
+	Dir     string `env:"BLOB_DIR" env-default:"./data/blobs"`
+	BaseURL string `env:"BLOB_BASE_URL" env-default:"/media/"`
+}
+
+type SchedulerConfig struct {
+	Interval time.Duration `env:"SCHEDULER_INTERVAL" env-default:"10s"`
+}
+
+type CommentConfig struct {
+	MaxDepth int `env:"COMMENT_MAX_DEPTH" env-default:"10"`
+}
+
+type SubscriptionConfig struct {
+	BufferSize     int    `env:"SUBSCRIPTION_BUFFER_SIZE" env-default:"64"`
+	OverflowPolicy string `env:"SUBSCRIPTION_OVERFLOW_POLICY" env-default:"drop-oldest"`
+}
+
+type ShutdownConfig struct {
+	Timeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
+}
+
+type LogConfig struct {
+	Level  string `env:"LOG_LEVEL"`
+	Format string `env:"LOG_FORMAT" env-default:"text"`
+}
+
+type TracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER" env-default:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT"`
+	ServiceName  string  `env:"TRACING_SERVICE_NAME" env-default:"ozon-app"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+type QueryLimitsConfig struct {
+	MaxComplexity int   `env:"QUERY_MAX_COMPLEXITY" env-default:"5000"`
+	MaxDepth      int   `env:"QUERY_MAX_DEPTH" env-default:"10"`
+	MaxPageSize   int32 `env:"QUERY_MAX_PAGE_SIZE" env-default:"100"`
+}
+
+type RateLimitConfig struct {
+	Enabled      bool              `env:"RATE_LIMIT_ENABLED" env-default:"true"`
+	Store        string            `env:"RATE_LIMIT_STORE" env-default:"memory"`
+	Mutation     string            `env:"RATE_LIMIT_MUTATION" env-default:"60/1m"`
+	Subscription string            `env:"RATE_LIMIT_SUBSCRIPTION" env-default:"30/1m"`
+	Fields       map[string]string `env:"RATE_LIMIT_FIELDS" env-default:"createComment:10/1m,createPost:5/1m,createUser:3/1m"`
+
+	MaxSubscriptionsPerConnection int `env:"RATE_LIMIT_MAX_SUBSCRIPTIONS" env-default:"20"`
+}
+
+type PersistedQueryConfig struct {
+	Allowlist    bool          `env:"PERSISTED_QUERY_ALLOWLIST" env-default:"false"`
+	ManifestPath string        `env:"PERSISTED_QUERY_MANIFEST" env-default:"./persisted-queries.json"`
+	APQStore     string        `env:"APQ_STORE" env-default:"redis"`
+	APQTTL       time.Duration `env:"APQ_TTL" env-default:"24h"`
+
+	// APQMaxEntries and APQMaxQuerySize bound what clients can store in
+	// Redis. Larger queries are executed but not stored.
+	APQMaxEntries   int `env:"APQ_MAX_ENTRIES" env-default:"1000"`
+	APQMaxQuerySize int `env:"APQ_MAX_QUERY_SIZE" env-default:"16384"`
+}
+
+type MetricsConfig struct {
+	// Operations are reported under their own name, along with those of the
+	// persisted query allowlist. Other operations are reported as "other".
+	Operations []string `env:"METRICS_OPERATIONS"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string      `env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods []string      `env:"CORS_ALLOWED_METHODS" env-default:"GET,POST,OPTIONS"`
+	AllowedHeaders []string      `env:"CORS_ALLOWED_HEADERS" env-default:"Content-Type,X-Request-ID,X-User-ID"`
+	MaxAge         time.Duration `env:"CORS_MAX_AGE" env-default:"10m"`
+}
+
 type Config struct {
+	Env    environment    `env:"APP_ENV" env-default:"prod"`
 	Port   string         `env:"PORT"`
 	DBType databaseType   `env:"DB_TYPE"`
 	DB     DatabaseConfig `env:"-"`
 	RedisConfig
+	BlobStoreConfig
+	SchedulerConfig
+	ShutdownConfig
+	SubscriptionConfig
+	CommentConfig
+	LogConfig
+	TracingConfig
+	QueryLimitsConfig
+	RateLimitConfig
+	PersistedQueryConfig
+	MetricsConfig
+	CORSConfig
+
+	// TrustedProxies is the number of reverse proxies in front of the
+	// service, each appending to X-Forwarded-For. With any, the scheme of
+	// requests is taken from X-Forwarded-Proto as well.
+	TrustedProxies int `env:"TRUSTED_PROXIES" env-default:"0"`
+
+	// AdminToken, when set, enables introspection for requests carrying it
+	// even where the profile disables it.
+	AdminToken string `env:"ADMIN_TOKEN"`
+}
+
+// Profile returns the settings of the configured environment.
+func (c *Config) Profile() Profile {
+	return profiles[c.Env]
 }
 
 func LoadConfig() (*Config, error) {
@@ -58,6 +191,14 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to load base config: %w", err)
 	}
 
+	profile, ok := profiles[cfg.Env]
+	if !ok {
+		return nil, fmt.Errorf("unknown environment: %s", cfg.Env)
+	}
+	if cfg.LogConfig.Level == "" {
+		cfg.LogConfig.Level = profile.LogLevel
+	}
+
 	switch cfg.DBType {
 	case postgres:
 		var pgConfig PostgresConfig
@@ -0,0 +1,135 @@
+package cors
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const wildcard = "*"
+
+// Policy decides which foreign origins may call the API from a browser.
+// Requests without an Origin header, and requests from the origin serving
+// the API, are never restricted.
+type Policy struct {
+	// AllowedOrigins holds origins such as "https://example.com"; "*"
+	// allows any origin.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration
+
+	// TrustForwardedProto takes the scheme of requests from the
+	// X-Forwarded-Proto header of a proxy terminating TLS.
+	TrustForwardedProto bool
+}
+
+func (p Policy) AllowsOrigin(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == wildcard || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOrigin is meant for websocket.Upgrader: unlike plain HTTP requests,
+// websocket handshakes are not covered by the browser's CORS checks, so
+// connections from origins off the list are refused here.
+func (p Policy) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || p.sameOrigin(origin, r) || p.AllowsOrigin(origin)
+}
+
+// Middleware answers preflight requests and adds CORS headers to responses
+// for allowed origins. Responses to other origins carry no CORS headers, so
+// browsers do not expose them; disallowed preflights are refused outright.
+func (p Policy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || p.sameOrigin(origin, r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := p.AllowsOrigin(origin)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if !allowed || !p.allowsPreflight(r) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", p.allowOrigin(origin))
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+			if p.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", p.allowOrigin(origin))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (p Policy) allowOrigin(origin string) string {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == wildcard {
+			return wildcard
+		}
+	}
+	return origin
+}
+
+func (p Policy) allowsPreflight(r *http.Request) bool {
+	if !containsFold(p.AllowedMethods, r.Header.Get("Access-Control-Request-Method")) {
+		return false
+	}
+
+	for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !containsFold(p.AllowedHeaders, header) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOrigin compares scheme and host, since http://host is a different
+// origin than https://host.
+func (p Policy) sameOrigin(origin string, r *http.Request) bool {
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Scheme, p.scheme(r)) && strings.EqualFold(u.Host, r.Host)
+}
+
+func (p Policy) scheme(r *http.Request) string {
+	if p.TrustForwardedProto {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			first, _, _ := strings.Cut(proto, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,167 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policy = Policy{
+	AllowedOrigins: []string{"https://app.example.com"},
+	AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+	AllowedHeaders: []string{"Content-Type", "X-Request-ID"},
+	MaxAge:         10 * time.Minute,
+}
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func preflight(origin, method, headers string) *http.Request {
+	r := httptest.NewRequest(http.MethodOptions, "http://api.example.com/query", nil)
+	r.Header.Set("Origin", origin)
+	r.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		r.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return r
+}
+
+func TestPreflight(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+	}{
+		{"allowed", preflight("https://app.example.com", "POST", "content-type, x-request-id"), http.StatusNoContent},
+		{"origin not allowed", preflight("https://evil.example.com", "POST", "Content-Type"), http.StatusForbidden},
+		{"method not allowed", preflight("https://app.example.com", "DELETE", ""), http.StatusForbidden},
+		{"header not allowed", preflight("https://app.example.com", "POST", "Authorization"), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			policy.Middleware(ok).ServeHTTP(rec, tt.req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code != http.StatusNoContent {
+				assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+				return
+			}
+			assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "GET, POST, OPTIONS", rec.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "Content-Type, X-Request-ID", rec.Header().Get("Access-Control-Allow-Headers"))
+			assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+		})
+	}
+}
+
+func TestActualRequest(t *testing.T) {
+	post := func(origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "http://api.example.com/query", strings.NewReader("{}"))
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		policy.Middleware(ok).ServeHTTP(rec, r)
+		return rec
+	}
+
+	t.Run("allowed origin", func(t *testing.T) {
+		rec := post("https://app.example.com")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("other origin gets no cors headers", func(t *testing.T) {
+		rec := post("https://evil.example.com")
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("same origin and non-browser clients pass", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("http://api.example.com").Code)
+		assert.Equal(t, http.StatusOK, post("").Code)
+	})
+
+	t.Run("same host over another scheme is cross-origin", func(t *testing.T) {
+		rec := post("https://api.example.com")
+		assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("scheme from trusted proxy", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://api.example.com/query", nil)
+		r.Header.Set("Origin", "https://api.example.com")
+		r.Header.Set("X-Forwarded-Proto", "https")
+
+		trusting := policy
+		trusting.TrustForwardedProto = true
+		assert.True(t, trusting.sameOrigin("https://api.example.com", r))
+		assert.False(t, policy.sameOrigin("https://api.example.com", r))
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://api.example.com/query", nil)
+		r.Header.Set("Origin", "https://anyone.example.com")
+		rec := httptest.NewRecorder()
+		Policy{AllowedOrigins: []string{"*"}}.Middleware(ok).ServeHTTP(rec, r)
+		assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestWebsocketUpgrade(t *testing.T) {
+	upgrader := websocket.Upgrader{CheckOrigin: policy.CheckOrigin}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	dial := func(origin string) (*http.Response, error) {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+		if conn != nil {
+			conn.Close()
+		}
+		return resp, err
+	}
+
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{"allowed origin", "https://app.example.com", true},
+		{"same origin", server.URL, true},
+		{"no origin", "", true},
+		{"foreign origin", "https://evil.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := dial(tt.origin)
+			if tt.allowed {
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+				return
+			}
+			assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package diff
+
+import "strings"
+
+type Op string
+
+const (
+	OpEqual  Op = "EQUAL"
+	OpInsert Op = "INSERT"
+	OpDelete Op = "DELETE"
+)
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxTableCells bounds the memory spent on the LCS table. Inputs whose
+// changed middle part is larger than that are reported as a full
+// replacement, which is still a correct, if coarse, diff.
+const maxTableCells = 4 << 20
+
+// Lines returns a line-based diff that turns a into b.
+func Lines(a, b string) []Line {
+	return diff(splitLines(a), splitLines(b))
+}
+
+func diff(a, b []string) []Line {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		result = append(result, Line{Op: OpEqual, Text: text})
+	}
+
+	result = append(result, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: OpEqual, Text: text})
+	}
+
+	return result
+}
+
+func diffMiddle(a, b []string) []Line {
+	if len(a)*len(b) > maxTableCells {
+		return replace(a, b)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+
+	return append(result, replace(a[i:], b[j:])...)
+}
+
+func replace(a, b []string) []Line {
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		result = append(result, Line{Op: OpDelete, Text: text})
+	}
+	for _, text := range b {
+		result = append(result, Line{Op: OpInsert, Text: text})
+	}
+	return result
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,55 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	t.Run("equal", func(t *testing.T) {
+		result := Lines("a\nb", "a\nb")
+		assert.Equal(t, []Line{{OpEqual, "a"}, {OpEqual, "b"}}, result)
+	})
+
+	t.Run("insert and delete", func(t *testing.T) {
+		result := Lines("a\nb\nc", "a\nc\nd")
+		assert.Equal(t, []Line{
+			{OpEqual, "a"},
+			{OpDelete, "b"},
+			{OpEqual, "c"},
+			{OpInsert, "d"},
+		}, result)
+	})
+
+	t.Run("changed line", func(t *testing.T) {
+		result := Lines("title\nold\nend\n", "title\nnew\nend\n")
+		assert.Equal(t, []Line{
+			{OpEqual, "title"},
+			{OpDelete, "old"},
+			{OpInsert, "new"},
+			{OpEqual, "end"},
+		}, result)
+	})
+
+	t.Run("from empty", func(t *testing.T) {
+		result := Lines("", "a\nb")
+		assert.Equal(t, []Line{{OpInsert, "a"}, {OpInsert, "b"}}, result)
+	})
+
+	t.Run("to empty", func(t *testing.T) {
+		result := Lines("a", "")
+		assert.Equal(t, []Line{{OpDelete, "a"}}, result)
+	})
+
+	t.Run("too large falls back to replacement", func(t *testing.T) {
+		a := strings.Repeat("x\n", 3000) + "a"
+		b := strings.Repeat("y\n", 3000) + "b"
+
+		result := Lines(a, b)
+		assert.Len(t, result, 6002)
+		assert.Equal(t, OpDelete, result[0].Op)
+		assert.Equal(t, OpInsert, result[len(result)-1].Op)
+	})
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxFilenameLength = 255
+
+type AttachmentOwner string
+
+const (
+	AttachmentOwnerPost    AttachmentOwner = "post"
+	AttachmentOwnerComment AttachmentOwner = "comment"
+)
+
+type Attachment struct {
+	Id           uuid.UUID       `db:"id"`
+	OwnerType    AttachmentOwner `db:"owner_type"`
+	OwnerId      uuid.UUID       `db:"owner_id"`
+	UserId       uuid.UUID       `db:"user_id"`
+	Filename     string          `db:"filename"`
+	ContentType  string          `db:"content_type"`
+	Size         int64           `db:"size"`
+	BlobKey      string          `db:"blob_key"`
+	ThumbnailKey string          `db:"thumbnail_key"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
+
+func NewAttachment(ownerType AttachmentOwner, ownerId, userId uuid.UUID, filename, contentType string, size int64) (*Attachment, error) {
+	attachment := &Attachment{
+		Id:          uuid.New(),
+		OwnerType:   ownerType,
+		OwnerId:     ownerId,
+		UserId:      userId,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := attachment.Validate(); err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (a *Attachment) Validate() error {
+	if a.OwnerType != AttachmentOwnerPost && a.OwnerType != AttachmentOwnerComment {
+		return ErrInvalidAttachmentOwner
+	}
+	if a.OwnerId == uuid.Nil {
+		return ErrInvalidAttachmentOwner
+	}
+	if a.UserId == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if a.Filename == "" {
+		return ErrEmptyFilename
+	}
+	if len([]rune(a.Filename)) > maxFilenameLength {
+		return ErrFilenameTooLong
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentEntity(t *testing.T) {
+	ownerId := uuid.New()
+	userId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		attachment, err := NewAttachment(AttachmentOwnerPost, ownerId, userId, "photo.png", "image/png", 1024)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, attachment)
+		assert.NotEqual(t, uuid.Nil, attachment.Id)
+		assert.Equal(t, AttachmentOwnerPost, attachment.OwnerType)
+		assert.Equal(t, ownerId, attachment.OwnerId)
+		assert.Equal(t, "photo.png", attachment.Filename)
+		assert.Equal(t, int64(1024), attachment.Size)
+		assert.False(t, attachment.CreatedAt.IsZero())
+	})
+
+	t.Run("invalid owner type", func(t *testing.T) {
+		attachment, err := NewAttachment("user", ownerId, userId, "photo.png", "image/png", 1024)
+
+		assert.Nil(t, attachment)
+		assert.ErrorIs(t, err, ErrInvalidAttachmentOwner)
+	})
+
+	t.Run("nil owner id", func(t *testing.T) {
+		attachment, err := NewAttachment(AttachmentOwnerComment, uuid.Nil, userId, "photo.png", "image/png", 1024)
+
+		assert.Nil(t, attachment)
+		assert.ErrorIs(t, err, ErrInvalidAttachmentOwner)
+	})
+
+	t.Run("nil user id", func(t *testing.T) {
+		attachment, err := NewAttachment(AttachmentOwnerComment, ownerId, uuid.Nil, "photo.png", "image/png", 1024)
+
+		assert.Nil(t, attachment)
+		assert.ErrorIs(t, err, ErrInvalidUserID)
+	})
+
+	t.Run("empty filename", func(t *testing.T) {
+		attachment, err := NewAttachment(AttachmentOwnerPost, ownerId, userId, "", "image/png", 1024)
+
+		assert.Nil(t, attachment)
+		assert.ErrorIs(t, err, ErrEmptyFilename)
+	})
+
+	t.Run("filename too long", func(t *testing.T) {
+		attachment, err := NewAttachment(AttachmentOwnerPost, ownerId, userId, strings.Repeat("a", 256), "image/png", 1024)
+
+		assert.Nil(t, attachment)
+		assert.ErrorIs(t, err, ErrFilenameTooLong)
+	})
+}
@@ -1,11 +1,14 @@
 package entity
 
 import (
+	"app/internal/markdown"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const maxRenderedCommentSize = 10 << 10
+
 type Comment struct {
 	Id        uuid.UUID  `db:"id"`
 	UserId    uuid.UUID  `db:"user_id"`
@@ -13,6 +16,22 @@ type Comment struct {
 	ParentId  *uuid.UUID `db:"parent_id"`
 	Content   string     `db:"content"`
 	CreatedAt time.Time  `db:"created_at"`
+	EditedAt  *time.Time `db:"edited_at"`
+	// Locked freezes the sub-thread: no replies can be added to this comment
+	// or to any comment below it.
+	Locked bool `db:"locked"`
+	// Path lists the ancestors from the thread root down to the parent, so
+	// that the whole chain is known without walking ParentId links.
+	Path  []uuid.UUID `db:"path"`
+	Depth int         `db:"depth"`
+}
+
+// CommentEdit keeps the content a comment had before one of its edits.
+type CommentEdit struct {
+	Id        uuid.UUID `db:"id"`
+	CommentId uuid.UUID `db:"comment_id"`
+	Content   string    `db:"content"`
+	CreatedAt time.Time `db:"created_at"`
 }
 
 func NewComment(userId, postId uuid.UUID, parentId *uuid.UUID, content string) (*Comment, error) {
@@ -23,6 +42,7 @@ func NewComment(userId, postId uuid.UUID, parentId *uuid.UUID, content string) (
 		ParentId:  parentId,
 		Content:   content,
 		CreatedAt: time.Now(),
+		Path:      []uuid.UUID{},
 	}
 
 	if err := comment.Validate(); err != nil {
@@ -32,6 +52,37 @@ func NewComment(userId, postId uuid.UUID, parentId *uuid.UUID, content string) (
 	return comment, nil
 }
 
+// ReplyTo places the comment under the parent and inherits its ancestry.
+func (c *Comment) ReplyTo(parent *Comment) {
+	parentId := parent.Id
+	c.ParentId = &parentId
+	c.Path = append(append(make([]uuid.UUID, 0, len(parent.Path)+1), parent.Path...), parent.Id)
+	c.Depth = parent.Depth + 1
+}
+
+// Edit replaces the content and marks the comment as edited at the given
+// time. Unchanged content leaves the comment untouched, and Edit reports
+// whether anything changed.
+func (c *Comment) Edit(content string, at time.Time) (bool, error) {
+	if content == c.Content {
+		return false, nil
+	}
+
+	edited := *c
+	edited.Content = content
+	edited.EditedAt = &at
+	if err := edited.Validate(); err != nil {
+		return false, err
+	}
+
+	*c = edited
+	return true, nil
+}
+
+func (c *Comment) IsEdited() bool {
+	return c.EditedAt != nil
+}
+
 func (c *Comment) Validate() error {
 	if c.UserId == uuid.Nil {
 		return ErrInvalidUserID
@@ -42,8 +93,14 @@ func (c *Comment) Validate() error {
 	if c.Content == "" {
 		return ErrEmptyContent
 	}
+	if c.Depth != len(c.Path) {
+		return ErrInvalidCommentPath
+	}
 	if len(c.Content) > 2000 {
 		return ErrCommentTooLong
 	}
+	if len(markdown.ToHTML(c.Content)) > maxRenderedCommentSize {
+		return ErrRenderedContentTooLarge
+	}
 	return nil
 }
@@ -3,6 +3,7 @@ package entity
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -41,6 +42,21 @@ func TestCommentEntity(t *testing.T) {
 		assert.ErrorIs(t, err, ErrCommentTooLong)
 	})
 
+	t.Run("rendered content too large", func(t *testing.T) {
+		content := strings.Repeat("[](b)", 400)
+		comment, err := NewComment(validUserId, validPostId, nilParentId, content)
+
+		assert.Nil(t, comment)
+		assert.ErrorIs(t, err, ErrRenderedContentTooLarge)
+	})
+
+	t.Run("markdown content", func(t *testing.T) {
+		comment, err := NewComment(validUserId, validPostId, nilParentId, "**bold** and [link](https://example.com)")
+
+		assert.NoError(t, err)
+		assert.NotNil(t, comment)
+	})
+
 	t.Run("nil user id", func(t *testing.T) {
 		comment, err := NewComment(uuid.Nil, validPostId, nilParentId, validContent)
 
@@ -64,3 +80,59 @@ func TestCommentEntity(t *testing.T) {
 		assert.Equal(t, parentId, *comment.ParentId)
 	})
 }
+
+func TestCommentEdit(t *testing.T) {
+	comment, err := NewComment(uuid.New(), uuid.New(), nil, "original")
+	assert.NoError(t, err)
+	assert.False(t, comment.IsEdited())
+
+	t.Run("success", func(t *testing.T) {
+		at := time.Now()
+		changed, err := comment.Edit("changed", at)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "changed", comment.Content)
+		assert.True(t, comment.IsEdited())
+		assert.Equal(t, at, *comment.EditedAt)
+	})
+
+	t.Run("invalid content keeps comment intact", func(t *testing.T) {
+		_, err := comment.Edit("", time.Now())
+		assert.ErrorIs(t, err, ErrEmptyContent)
+		assert.Equal(t, "changed", comment.Content)
+	})
+
+	t.Run("unchanged content is a no-op", func(t *testing.T) {
+		editedAt := *comment.EditedAt
+		changed, err := comment.Edit("changed", editedAt.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, editedAt, *comment.EditedAt)
+	})
+}
+
+func TestCommentReplyTo(t *testing.T) {
+	root, err := NewComment(uuid.New(), uuid.New(), nil, "root")
+	assert.NoError(t, err)
+	assert.Empty(t, root.Path)
+	assert.Zero(t, root.Depth)
+
+	reply, err := NewComment(uuid.New(), root.PostId, &root.Id, "reply")
+	assert.NoError(t, err)
+	reply.ReplyTo(root)
+
+	nested, err := NewComment(uuid.New(), root.PostId, &reply.Id, "nested")
+	assert.NoError(t, err)
+	nested.ReplyTo(reply)
+
+	assert.Equal(t, []uuid.UUID{root.Id}, reply.Path)
+	assert.Equal(t, 1, reply.Depth)
+	assert.Equal(t, []uuid.UUID{root.Id, reply.Id}, nested.Path)
+	assert.Equal(t, 2, nested.Depth)
+	assert.Equal(t, reply.Id, *nested.ParentId)
+	assert.Len(t, reply.Path, 1, "parent path must not be shared with children")
+	assert.NoError(t, nested.Validate())
+
+	nested.Depth = 5
+	assert.ErrorIs(t, nested.Validate(), ErrInvalidCommentPath)
+}
@@ -9,6 +9,22 @@ var (
 	ErrInvalidUserID  = errors.New("invalid user ID")
 	ErrInvalidPostID  = errors.New("invalid post ID")
 	ErrCommentTooLong = errors.New("comment is too long")
+
+	ErrInvalidCommentPath = errors.New("comment depth does not match its path")
+
+	ErrRenderedContentTooLarge = errors.New("rendered content is too large")
+
+	ErrInvalidPostStatus    = errors.New("invalid post status")
+	ErrMissingPublishTime   = errors.New("publish time is required")
+	ErrPublishTimeInPast    = errors.New("publish time must be in the future")
+	ErrPostAlreadyPublished = errors.New("post is already published")
+
+	ErrDisplayNameTooLong = errors.New("display name is too long")
+	ErrBioTooLong         = errors.New("bio is too long")
+
+	ErrInvalidAttachmentOwner = errors.New("invalid attachment owner")
+	ErrEmptyFilename          = errors.New("filename cannot be empty")
+	ErrFilenameTooLong        = errors.New("filename is too long")
 )
 
 type Entity interface {
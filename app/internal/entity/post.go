@@ -1,27 +1,63 @@
 package entity
 
 import (
+	"app/internal/markdown"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// maxRenderedPostSize bounds the HTML produced from the Markdown content,
+// which can be several times larger than the source.
+const maxRenderedPostSize = 64 << 10
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "DRAFT"
+	PostStatusScheduled PostStatus = "SCHEDULED"
+	PostStatusPublished PostStatus = "PUBLISHED"
+)
+
 type Post struct {
-	Id            uuid.UUID `db:"id"`
-	UserId        uuid.UUID `db:"user_id"`
-	Title         string    `db:"title"`
-	Content       string    `db:"content"`
-	IsCommentable bool      `db:"is_commentable"`
-	CreatedAt     time.Time `db:"created_at"`
+	Id            uuid.UUID  `db:"id"`
+	UserId        uuid.UUID  `db:"user_id"`
+	Title         string     `db:"title"`
+	Content       string     `db:"content"`
+	IsCommentable bool       `db:"is_commentable"`
+	Status        PostStatus `db:"status"`
+	PublishAt     *time.Time `db:"publish_at"`
+	CreatedAt     time.Time  `db:"created_at"`
 }
 
 func NewPost(userId uuid.UUID, title string, content string, isCommentable bool) (*Post, error) {
+	now := time.Now()
+	post := &Post{
+		Id:            uuid.New(),
+		UserId:        userId,
+		Title:         title,
+		Content:       content,
+		IsCommentable: isCommentable,
+		Status:        PostStatusPublished,
+		PublishAt:     &now,
+		CreatedAt:     now,
+	}
+
+	if err := post.Validate(); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+func NewDraft(userId uuid.UUID, title string, content string, isCommentable bool) (*Post, error) {
 	post := &Post{
 		Id:            uuid.New(),
 		UserId:        userId,
 		Title:         title,
 		Content:       content,
 		IsCommentable: isCommentable,
+		Status:        PostStatusDraft,
 		CreatedAt:     time.Now(),
 	}
 
@@ -32,6 +68,31 @@ func NewPost(userId uuid.UUID, title string, content string, isCommentable bool)
 	return post, nil
 }
 
+func (p *Post) IsPublished() bool {
+	return p.Status == PostStatusPublished
+}
+
+func (p *Post) Publish(at time.Time) error {
+	if p.IsPublished() {
+		return ErrPostAlreadyPublished
+	}
+	p.Status = PostStatusPublished
+	p.PublishAt = &at
+	return nil
+}
+
+func (p *Post) Schedule(at time.Time, now time.Time) error {
+	if p.IsPublished() {
+		return ErrPostAlreadyPublished
+	}
+	if !at.After(now) {
+		return ErrPublishTimeInPast
+	}
+	p.Status = PostStatusScheduled
+	p.PublishAt = &at
+	return nil
+}
+
 func (p *Post) Validate() error {
 	if p.Title == "" {
 		return ErrEmptyTitle
@@ -45,5 +106,19 @@ func (p *Post) Validate() error {
 		return ErrInvalidUserID
 	}
 
+	switch p.Status {
+	case PostStatusDraft:
+	case PostStatusScheduled, PostStatusPublished:
+		if p.PublishAt == nil {
+			return ErrMissingPublishTime
+		}
+	default:
+		return ErrInvalidPostStatus
+	}
+
+	if len(markdown.ToHTML(p.Content)) > maxRenderedPostSize {
+		return ErrRenderedContentTooLarge
+	}
+
 	return nil
 }
@@ -1,7 +1,9 @@
 package entity
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -45,13 +47,96 @@ func TestPostEntity(t *testing.T) {
 		assert.ErrorIs(t, err, ErrInvalidUserID)
 	})
 
+	t.Run("rendered content too large", func(t *testing.T) {
+		post, err := NewPost(validUserId, validTitle, strings.Repeat("[](b)", 14000), true)
+
+		assert.Nil(t, post)
+		assert.ErrorIs(t, err, ErrRenderedContentTooLarge)
+	})
+
 	t.Run("validate method", func(t *testing.T) {
 		post := &Post{
 			UserId:  validUserId,
 			Title:   validTitle,
 			Content: validContent,
+			Status:  PostStatusDraft,
 		}
 
 		assert.NoError(t, post.Validate())
 	})
+
+	t.Run("published post requires publish time", func(t *testing.T) {
+		post := &Post{
+			UserId:  validUserId,
+			Title:   validTitle,
+			Content: validContent,
+			Status:  PostStatusPublished,
+		}
+
+		assert.ErrorIs(t, post.Validate(), ErrMissingPublishTime)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		post := &Post{
+			UserId:  validUserId,
+			Title:   validTitle,
+			Content: validContent,
+		}
+
+		assert.ErrorIs(t, post.Validate(), ErrInvalidPostStatus)
+	})
+}
+
+func TestPostPublishing(t *testing.T) {
+	userId := uuid.New()
+	now := time.Now()
+
+	t.Run("new post is published", func(t *testing.T) {
+		post, err := NewPost(userId, "Title", "Content", true)
+
+		assert.NoError(t, err)
+		assert.True(t, post.IsPublished())
+		assert.NotNil(t, post.PublishAt)
+	})
+
+	t.Run("draft is not published", func(t *testing.T) {
+		draft, err := NewDraft(userId, "Title", "Content", true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, PostStatusDraft, draft.Status)
+		assert.False(t, draft.IsPublished())
+		assert.Nil(t, draft.PublishAt)
+	})
+
+	t.Run("publish draft", func(t *testing.T) {
+		draft, _ := NewDraft(userId, "Title", "Content", true)
+
+		assert.NoError(t, draft.Publish(now))
+		assert.True(t, draft.IsPublished())
+		assert.Equal(t, now, *draft.PublishAt)
+		assert.ErrorIs(t, draft.Publish(now), ErrPostAlreadyPublished)
+	})
+
+	t.Run("schedule draft", func(t *testing.T) {
+		draft, _ := NewDraft(userId, "Title", "Content", true)
+		publishAt := now.Add(time.Hour)
+
+		assert.NoError(t, draft.Schedule(publishAt, now))
+		assert.Equal(t, PostStatusScheduled, draft.Status)
+		assert.Equal(t, publishAt, *draft.PublishAt)
+		assert.NoError(t, draft.Validate())
+	})
+
+	t.Run("schedule in the past", func(t *testing.T) {
+		draft, _ := NewDraft(userId, "Title", "Content", true)
+
+		assert.ErrorIs(t, draft.Schedule(now.Add(-time.Minute), now), ErrPublishTimeInPast)
+		assert.Equal(t, PostStatusDraft, draft.Status)
+	})
+
+	t.Run("schedule published post", func(t *testing.T) {
+		post, _ := NewPost(userId, "Title", "Content", true)
+
+		assert.ErrorIs(t, post.Schedule(now.Add(time.Hour), now), ErrPostAlreadyPublished)
+	})
 }
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostRevision is a snapshot of a post's title and content taken right
+// before an update replaced them.
+type PostRevision struct {
+	Id        uuid.UUID `db:"id"`
+	PostId    uuid.UUID `db:"post_id"`
+	Title     string    `db:"title"`
+	Content   string    `db:"content"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func NewPostRevision(post *Post) *PostRevision {
+	return &PostRevision{
+		Id:        uuid.New(),
+		PostId:    post.Id,
+		Title:     post.Title,
+		Content:   post.Content,
+		CreatedAt: time.Now(),
+	}
+}
+
+// HasSameText reports whether an update to the post would leave its title
+// and content untouched, in which case no revision is worth keeping.
+func (p *Post) HasSameText(other *Post) bool {
+	return p.Title == other.Title && p.Content == other.Content
+}
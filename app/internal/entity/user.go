@@ -1,20 +1,37 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+const (
+	maxDisplayNameLength = 64
+	maxBioLength         = 500
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+)
+
 type User struct {
-	Id       uuid.UUID `db:"id"`
-	Username string    `db:"username"`
-	Roles    []string  `db:"roles"`
+	Id          uuid.UUID `db:"id"`
+	Username    string    `db:"username"`
+	Roles       []string  `db:"roles"`
+	CreatedAt   time.Time `db:"created_at"`
+	DisplayName string    `db:"display_name"`
+	Bio         string    `db:"bio"`
+	AvatarKey   string    `db:"avatar_key"`
 }
 
 func NewUser(username string) (*User, error) {
 	user := &User{
-		Id:       uuid.New(),
-		Username: username,
-		Roles:    []string{"user"},
+		Id:        uuid.New(),
+		Username:  username,
+		Roles:     []string{RoleUser},
+		CreatedAt: time.Now(),
 	}
 
 	if err := user.Validate(); err != nil {
@@ -24,10 +41,31 @@ func NewUser(username string) (*User, error) {
 	return user, nil
 }
 
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) IsModerator() bool {
+	return u.HasRole(RoleModerator)
+}
+
 func (u *User) Validate() error {
 	if u.Username == "" {
 		return ErrEmptyUsername
 	}
 
+	if len([]rune(u.DisplayName)) > maxDisplayNameLength {
+		return ErrDisplayNameTooLong
+	}
+
+	if len([]rune(u.Bio)) > maxBioLength {
+		return ErrBioTooLong
+	}
+
 	return nil
 }
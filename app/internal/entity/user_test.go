@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,7 @@ func TestUserEntity(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, username, expectedUser.Username)
+		assert.False(t, expectedUser.CreatedAt.IsZero())
 	})
 
 	t.Run("Empty username", func(t *testing.T) {
@@ -21,4 +23,24 @@ func TestUserEntity(t *testing.T) {
 		assert.Nil(t, expectedUser)
 		assert.ErrorIs(t, err, ErrEmptyUsername)
 	})
+
+	t.Run("Display name too long", func(t *testing.T) {
+		user := &User{Username: "User", DisplayName: strings.Repeat("a", 65)}
+		assert.ErrorIs(t, user.Validate(), ErrDisplayNameTooLong)
+	})
+
+	t.Run("Bio too long", func(t *testing.T) {
+		user := &User{Username: "User", Bio: strings.Repeat("a", 501)}
+		assert.ErrorIs(t, user.Validate(), ErrBioTooLong)
+	})
+}
+
+func TestUserRoles(t *testing.T) {
+	user, err := NewUser("moder")
+	assert.NoError(t, err)
+	assert.True(t, user.HasRole(RoleUser))
+	assert.False(t, user.IsModerator())
+
+	user.Roles = append(user.Roles, RoleModerator)
+	assert.True(t, user.IsModerator())
 }
@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+const checkTimeout = 2 * time.Second
+
+// Check reports whether one backend the application depends on is usable.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Health serves liveness and readiness probes.
+type Health struct {
+	checks   []Check
+	logger   *slog.Logger
+	draining atomic.Bool
+}
+
+func New(logger *slog.Logger, checks ...Check) *Health {
+	return &Health{checks: checks, logger: logger}
+}
+
+// SetDraining makes readiness fail so that no new traffic is routed to the
+// process while it shuts down.
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+type CheckResult struct {
+	Status string `json:"status"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Run executes all checks concurrently. Errors are logged rather than
+// reported, since the probes are served to anyone who can reach them.
+func (h *Health) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(h.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := CheckResult{Status: StatusOK}
+			if err := check.Check(ctx); err != nil {
+				h.logger.WarnContext(ctx, "Health check failed", "check", check.Name, "error", err)
+				result = CheckResult{Status: StatusUnavailable}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// LivenessHandler answers 200 as long as the process can serve requests. It
+// runs no checks: a failing backend does not make the process worth
+// restarting.
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, Report{Status: StatusOK})
+	})
+}
+
+// ReadinessHandler answers 503 when a backend is down or the process is
+// draining.
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.Run(r.Context())
+		code := http.StatusOK
+		if report.Status != StatusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeReport(w, code, report)
+	})
+}
+
+func writeReport(w http.ResponseWriter, code int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
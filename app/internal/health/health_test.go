@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, handler http.Handler) (int, Report) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	return rec.Code, report
+}
+
+func TestHealth(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	up := Check{Name: "postgres", Check: func(context.Context) error { return nil }}
+	down := Check{Name: "redis", Check: func(context.Context) error { return errors.New("connection refused") }}
+
+	t.Run("ready", func(t *testing.T) {
+		code, report := serve(t, New(logger, up).ReadinessHandler())
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, Report{
+			Status: StatusOK,
+			Checks: map[string]CheckResult{"postgres": {Status: StatusOK}},
+		}, report)
+	})
+
+	t.Run("backend down", func(t *testing.T) {
+		code, report := serve(t, New(logger, up, down).ReadinessHandler())
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Equal(t, CheckResult{Status: StatusUnavailable}, report.Checks["redis"])
+		assert.Equal(t, CheckResult{Status: StatusOK}, report.Checks["postgres"])
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		h := New(logger, up)
+		h.SetDraining()
+		code, report := serve(t, h.ReadinessHandler())
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDraining, report.Status)
+	})
+
+	t.Run("liveness runs no checks", func(t *testing.T) {
+		unexpected := Check{Name: "redis", Check: func(context.Context) error {
+			t.Error("liveness ran a backend check")
+			return nil
+		}}
+		code, report := serve(t, New(logger, unexpected).LivenessHandler())
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, Report{Status: StatusOK}, report)
+	})
+
+	t.Run("error details are not exposed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		New(logger, down).ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.NotContains(t, rec.Body.String(), "connection refused")
+	})
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Extension logs every GraphQL operation with its duration and errors, and
+// every resolver call at debug level.
+type Extension struct {
+	Logger *slog.Logger
+}
+
+var (
+	_ graphql.HandlerExtension     = Extension{}
+	_ graphql.OperationInterceptor = Extension{}
+	_ graphql.FieldInterceptor     = Extension{}
+)
+
+func (e Extension) ExtensionName() string {
+	return "Logging"
+}
+
+func (e Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	start := time.Now()
+	oc := graphql.GetOperationContext(ctx)
+	handler := next(ctx)
+
+	return func(ctx context.Context) *graphql.Response {
+		resp := handler(ctx)
+		if resp == nil {
+			return nil
+		}
+
+		attrs := []any{slog.Duration("duration", time.Since(start))}
+		if op := oc.Operation; op != nil {
+			attrs = append(attrs, slog.String("operation", op.Name), slog.String("type", string(op.Operation)))
+		}
+
+		if len(resp.Errors) > 0 {
+			messages := make([]string, 0, len(resp.Errors))
+			for _, err := range resp.Errors {
+				messages = append(messages, err.Message)
+			}
+			e.Logger.WarnContext(ctx, "GraphQL operation failed", append(attrs, slog.Any("errors", messages))...)
+		} else {
+			e.Logger.InfoContext(ctx, "GraphQL operation", attrs...)
+		}
+		return resp
+	}
+}
+
+func (e Extension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+
+	attrs := []any{
+		slog.String("field", fc.Path().String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		e.Logger.WarnContext(ctx, "Resolver failed", append(attrs, slog.Any("error", err))...)
+	} else {
+		e.Logger.DebugContext(ctx, "Resolver finished", attrs...)
+	}
+	return res, err
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("json with request id", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, "debug", "json")
+		require.NoError(t, err)
+
+		logger.DebugContext(WithRequestID(context.Background(), "req-1"), "Resolving post", "id", "42")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "Resolving post", record["msg"])
+		assert.Equal(t, "req-1", record["request_id"])
+		assert.Equal(t, "42", record["id"])
+	})
+
+	t.Run("level filters records", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, "warn", "text")
+		require.NoError(t, err)
+
+		logger.Info("hidden")
+		logger.Warn("shown")
+
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), "shown")
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := New(&bytes.Buffer{}, "verbose", "text")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := New(&bytes.Buffer{}, "info", "xml")
+		assert.Error(t, err)
+	})
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	t.Run("propagates header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.Header.Set(RequestIDHeader, "abc")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "abc", seen)
+		assert.Equal(t, "abc", rec.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("generates missing id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", nil))
+
+		assert.NotEmpty(t, seen)
+		assert.Equal(t, seen, rec.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("replaces oversized id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.Header.Set(RequestIDHeader, strings.Repeat("a", maxRequestIDLength+1))
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Len(t, seen, 36)
+	})
+}
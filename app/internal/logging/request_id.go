@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client supplied IDs so they cannot bloat logs.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+func WithRequestID(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestId)
+}
+
+func RequestID(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIDKey{}).(string)
+	return requestId
+}
+
+// RequestIDMiddleware takes the request ID from the X-Request-ID header or
+// generates one, echoes it in the response and stores it in the context.
+// Websocket operations inherit it from the upgrade request.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(RequestIDHeader)
+		if requestId == "" || len(requestId) > maxRequestIDLength {
+			requestId = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestId)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestId)))
+	})
+}
@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"crypto/sha256"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+type cacheKey struct {
+	id      string
+	version [sha256.Size]byte
+	format  Format
+}
+
+// Cache keeps rendered content per entity version. The version is a digest
+// of the source, so an edited entity gets a fresh entry while the stale one
+// is evicted in due course.
+type Cache struct {
+	entries *lru.Cache[cacheKey, string]
+}
+
+func NewCache(size int) (*Cache, error) {
+	entries, err := lru.New[cacheKey, string](size)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{entries: entries}, nil
+}
+
+func (c *Cache) Render(id string, source string, format Format) string {
+	key := cacheKey{id: id, version: sha256.Sum256([]byte(source)), format: format}
+	if rendered, ok := c.entries.Get(key); ok {
+		return rendered
+	}
+
+	rendered := Render(source, format)
+	c.entries.Add(key, rendered)
+	return rendered
+}
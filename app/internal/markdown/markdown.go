@@ -0,0 +1,73 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+type Format string
+
+const (
+	FormatHTML  Format = "HTML"
+	FormatPlain Format = "PLAIN"
+)
+
+// Goldmark renders plain CommonMark and already drops raw HTML, but the
+// output still goes through an allowlist so that nothing a parser quirk lets
+// through ends up in a client.
+var (
+	converter = goldmark.New()
+
+	htmlPolicy  = newHTMLPolicy()
+	plainPolicy = bluemonday.StrictPolicy()
+
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("p", "br", "hr", "em", "strong", "blockquote", "pre", "h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowLists()
+	policy.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	policy.AllowElements("code")
+	policy.AllowStandardURLs()
+	policy.AllowAttrs("href", "title").OnElements("a")
+	policy.AllowImages()
+	policy.AllowAttrs("title").OnElements("img")
+	return policy
+}
+
+// ToHTML renders CommonMark source into sanitized HTML. Links always carry
+// rel="nofollow" and only http, https and mailto URLs survive.
+func ToHTML(source string) string {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		// Goldmark only fails on writer errors, which bytes.Buffer never returns.
+		return html.EscapeString(source)
+	}
+	return htmlPolicy.Sanitize(buf.String())
+}
+
+// ToPlain renders CommonMark source and strips all markup, leaving the text
+// with blocks separated by newlines.
+func ToPlain(source string) string {
+	text := html.UnescapeString(plainPolicy.Sanitize(ToHTML(source)))
+	return strings.TrimSpace(blankLines.ReplaceAllString(text, "\n\n"))
+}
+
+func Render(source string, format Format) string {
+	switch format {
+	case FormatHTML:
+		return ToHTML(source)
+	case FormatPlain:
+		return ToPlain(source)
+	default:
+		return source
+	}
+}
@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "emphasis",
+			source:   "Hello *world* and **you**",
+			expected: "<p>Hello <em>world</em> and <strong>you</strong></p>\n",
+		},
+		{
+			name:     "links get nofollow",
+			source:   "[site](https://example.com)",
+			expected: "<p><a href=\"https://example.com\" rel=\"nofollow\">site</a></p>\n",
+		},
+		{
+			name:     "javascript links are dropped",
+			source:   "[click](javascript:alert(1))",
+			expected: "<p>click</p>\n",
+		},
+		{
+			name:     "raw html is removed",
+			source:   "<script>alert(1)</script>\n\n<a href=\"https://example.com\" onclick=\"steal()\">x</a>",
+			expected: "\n<p>x</p>\n",
+		},
+		{
+			name:     "code blocks are escaped",
+			source:   "```go\nif a < b {}\n```",
+			expected: "<pre><code class=\"language-go\">if a &lt; b {}\n</code></pre>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ToHTML(tt.source))
+		})
+	}
+}
+
+func TestToPlain(t *testing.T) {
+	source := "# Title\n\nSome *text* with a [link](https://example.com) & more\n\n- one\n- two"
+
+	assert.Equal(t, "Title\nSome text with a link & more\n\none\ntwo", ToPlain(source))
+}
+
+func TestRender(t *testing.T) {
+	source := "*raw*"
+
+	assert.Equal(t, "*raw*", Render(source, "RAW"))
+	assert.Equal(t, "<p><em>raw</em></p>\n", Render(source, FormatHTML))
+	assert.Equal(t, "raw", Render(source, FormatPlain))
+}
+
+func TestCache(t *testing.T) {
+	cache, err := NewCache(10)
+	require.NoError(t, err)
+
+	first := cache.Render("post-1", "*v1*", FormatHTML)
+	assert.Equal(t, "<p><em>v1</em></p>\n", first)
+	assert.Equal(t, first, cache.Render("post-1", "*v1*", FormatHTML))
+	assert.Equal(t, 1, cache.entries.Len())
+
+	assert.Equal(t, "<p><em>v2</em></p>\n", cache.Render("post-1", "*v2*", FormatHTML))
+	assert.Equal(t, "v2", cache.Render("post-1", "*v2*", FormatPlain))
+	assert.Equal(t, 3, cache.entries.Len())
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"app/internal/pubsub"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CommentSource exposes comment delivery counters and the subscriptions
+// held by this process.
+type CommentSource interface {
+	CommentStats() pubsub.CommentStats
+	SubscriptionsPerPost() map[string]int
+}
+
+var (
+	commentsPublishedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "comments", "published_total"),
+		"Comments published to the broker.", nil, nil)
+	commentsDeliveredDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "comments", "delivered_total"),
+		"Comments delivered to subscribers.", nil, nil)
+	commentsDroppedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "comments", "dropped_total"),
+		"Comments dropped on the way to a subscriber that fell behind.", nil, nil)
+	commentSubscriptionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "comments", "active_subscriptions"),
+		"Active comment subscriptions per post.", []string{"post_id"}, nil)
+)
+
+type commentCollector struct {
+	source CommentSource
+}
+
+func NewCommentCollector(source CommentSource) prometheus.Collector {
+	return commentCollector{source: source}
+}
+
+func (c commentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- commentsPublishedDesc
+	ch <- commentsDeliveredDesc
+	ch <- commentsDroppedDesc
+	ch <- commentSubscriptionsDesc
+}
+
+func (c commentCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.source.CommentStats()
+	ch <- prometheus.MustNewConstMetric(commentsPublishedDesc, prometheus.CounterValue, float64(stats.Published))
+	ch <- prometheus.MustNewConstMetric(commentsDeliveredDesc, prometheus.CounterValue, float64(stats.Delivered))
+	ch <- prometheus.MustNewConstMetric(commentsDroppedDesc, prometheus.CounterValue, float64(stats.Dropped))
+
+	for postId, count := range c.source.SubscriptionsPerPost() {
+		ch <- prometheus.MustNewConstMetric(commentSubscriptionsDesc, prometheus.GaugeValue, float64(count), postId)
+	}
+}
+
+// PoolSource is satisfied by *pgxpool.Pool.
+type PoolSource interface {
+	Stat() *pgxpool.Stat
+}
+
+var (
+	poolAcquiredDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquired_connections"),
+		"Connections currently in use.", nil, nil)
+	poolIdleDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "idle_connections"),
+		"Idle connections.", nil, nil)
+	poolTotalDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "total_connections"),
+		"Connections currently open.", nil, nil)
+	poolMaxDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "max_connections"),
+		"Maximum size of the pool.", nil, nil)
+	poolAcquiresDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquires_total"),
+		"Successful connection acquires.", nil, nil)
+	poolEmptyAcquiresDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "empty_acquires_total"),
+		"Acquires that had to wait for a connection.", nil, nil)
+	poolCanceledAcquiresDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "canceled_acquires_total"),
+		"Acquires canceled by their context.", nil, nil)
+	poolAcquireDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquire_duration_seconds_total"),
+		"Total time spent acquiring connections.", nil, nil)
+)
+
+type poolCollector struct {
+	pool PoolSource
+}
+
+func NewPoolCollector(pool PoolSource) prometheus.Collector {
+	return poolCollector{pool: pool}
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredDesc
+	ch <- poolIdleDesc
+	ch <- poolTotalDesc
+	ch <- poolMaxDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquiresDesc
+	ch <- poolCanceledAcquiresDesc
+	ch <- poolAcquireDurationDesc
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquiresDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquiresDesc, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireDurationDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Extension returns a gqlgen extension recording operation and resolver
+// latencies and errors.
+func (m *Metrics) Extension() graphql.HandlerExtension {
+	return extension{m}
+}
+
+type extension struct {
+	metrics *Metrics
+}
+
+var (
+	_ graphql.OperationInterceptor = extension{}
+	_ graphql.FieldInterceptor     = extension{}
+)
+
+func (e extension) ExtensionName() string {
+	return "Metrics"
+}
+
+func (e extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	start := time.Now()
+	oc := graphql.GetOperationContext(ctx)
+	handler := next(ctx)
+
+	var name, kind string
+	if op := oc.Operation; op != nil {
+		name, kind = e.metrics.operationLabel(op.Name), string(op.Operation)
+	}
+
+	return func(ctx context.Context) *graphql.Response {
+		resp := handler(ctx)
+		if resp == nil {
+			return nil
+		}
+
+		// Subscriptions produce a response per event, their duration is
+		// just the age of the subscription.
+		if kind != string(ast.Subscription) {
+			e.metrics.operationDuration.WithLabelValues(name, kind).Observe(time.Since(start).Seconds())
+		}
+		if len(resp.Errors) > 0 {
+			e.metrics.operationErrors.WithLabelValues(name, kind).Inc()
+		}
+		return resp
+	}
+}
+
+func (e extension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+
+	e.metrics.fieldDuration.WithLabelValues(fc.Object, fc.Field.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		e.metrics.fieldErrors.WithLabelValues(fc.Object, fc.Field.Name).Inc()
+	}
+	return res, err
+}
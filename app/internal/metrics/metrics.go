@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "app"
+
+// otherOperation labels operations whose name is not tracked.
+const otherOperation = "other"
+
+// Metrics owns the registry served on /metrics and the collectors fed by
+// the GraphQL handler.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	operationDuration *prometheus.HistogramVec
+	operationErrors   *prometheus.CounterVec
+	fieldDuration     *prometheus.HistogramVec
+	fieldErrors       *prometheus.CounterVec
+
+	// operations are the names reported under their own label.
+	operations map[string]struct{}
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "graphql",
+			Name:      "operation_duration_seconds",
+			Help:      "Time spent executing GraphQL operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "type"}),
+		operationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "graphql",
+			Name:      "operation_errors_total",
+			Help:      "GraphQL responses that carried at least one error.",
+		}, []string{"operation", "type"}),
+		fieldDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "graphql",
+			Name:      "field_duration_seconds",
+			Help:      "Time spent in GraphQL field resolvers.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"object", "field"}),
+		fieldErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "graphql",
+			Name:      "field_errors_total",
+			Help:      "GraphQL field resolvers that returned an error.",
+		}, []string{"object", "field"}),
+		operations: make(map[string]struct{}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.operationDuration,
+		m.operationErrors,
+		m.fieldDuration,
+		m.fieldErrors,
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// TrackOperations reports operations with the given names under their own
+// label. Clients choose operation names, so every other operation is
+// reported as "other" to keep the number of series bounded. It must be
+// called before requests are served.
+func (m *Metrics) TrackOperations(names ...string) {
+	for _, name := range names {
+		m.operations[name] = struct{}{}
+	}
+}
+
+func (m *Metrics) operationLabel(name string) string {
+	if _, tracked := m.operations[name]; tracked {
+		return name
+	}
+	return otherOperation
+}
+
+func (m *Metrics) Register(collector prometheus.Collector) {
+	m.registry.MustRegister(collector)
+}
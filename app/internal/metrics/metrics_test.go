@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"app/internal/pubsub"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type fakeCommentSource struct {
+	stats pubsub.CommentStats
+	subs  map[string]int
+}
+
+func (f fakeCommentSource) CommentStats() pubsub.CommentStats {
+	return f.stats
+}
+
+func (f fakeCommentSource) SubscriptionsPerPost() map[string]int {
+	return f.subs
+}
+
+func TestCommentCollector(t *testing.T) {
+	collector := NewCommentCollector(fakeCommentSource{
+		stats: pubsub.CommentStats{Published: 5, Delivered: 8, Dropped: 1},
+		subs:  map[string]int{"post-1": 2},
+	})
+
+	expected := `
+# HELP app_comments_active_subscriptions Active comment subscriptions per post.
+# TYPE app_comments_active_subscriptions gauge
+app_comments_active_subscriptions{post_id="post-1"} 2
+# HELP app_comments_delivered_total Comments delivered to subscribers.
+# TYPE app_comments_delivered_total counter
+app_comments_delivered_total 8
+# HELP app_comments_dropped_total Comments dropped on the way to a subscriber that fell behind.
+# TYPE app_comments_dropped_total counter
+app_comments_dropped_total 1
+# HELP app_comments_published_total Comments published to the broker.
+# TYPE app_comments_published_total counter
+app_comments_published_total 5
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.operationDuration.WithLabelValues("GetPost", "query").Observe(0.01)
+	m.fieldErrors.WithLabelValues("Query", "post").Inc()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	require.Equal(t, 200, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `app_graphql_operation_duration_seconds_count{operation="GetPost",type="query"} 1`)
+	assert.Contains(t, body, `app_graphql_field_errors_total{field="post",object="Query"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func runOperation(m *Metrics, name string) {
+	ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+		Operation: &ast.OperationDefinition{Name: name, Operation: ast.Query},
+	})
+	handler := m.Extension().(graphql.OperationInterceptor).InterceptOperation(ctx, func(ctx context.Context) graphql.ResponseHandler {
+		return func(ctx context.Context) *graphql.Response {
+			return &graphql.Response{}
+		}
+	})
+	handler(ctx)
+}
+
+func TestOperationLabel(t *testing.T) {
+	m := New()
+	m.TrackOperations("GetPost")
+
+	runOperation(m, "GetPost")
+	runOperation(m, uuid.NewString())
+	assert.Equal(t, 2, testutil.CollectAndCount(m.operationDuration))
+
+	// Untracked names share a single series.
+	runOperation(m, uuid.NewString())
+	assert.Equal(t, 2, testutil.CollectAndCount(m.operationDuration))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `app_graphql_operation_duration_seconds_count{operation="GetPost",type="query"} 1`)
+	assert.Contains(t, rec.Body.String(), `app_graphql_operation_duration_seconds_count{operation="other",type="query"} 2`)
+}
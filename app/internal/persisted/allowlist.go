@@ -0,0 +1,59 @@
+package persisted
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CodeNotAllowed is reported for operations missing from the manifest.
+const CodeNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// Allowlist only runs documents from the manifest. Clients send either the
+// hash in extensions.persistedQuery, as with APQ, or the exact document text.
+type Allowlist struct {
+	Manifest Manifest
+}
+
+var (
+	_ graphql.HandlerExtension          = Allowlist{}
+	_ graphql.OperationParameterMutator = Allowlist{}
+)
+
+func (a Allowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (a Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	var sentHash string
+	if ext, ok := rawParams.Extensions["persistedQuery"].(map[string]interface{}); ok {
+		sentHash, _ = ext["sha256Hash"].(string)
+	}
+
+	if rawParams.Query == "" {
+		query, ok := a.Manifest[sentHash]
+		if !ok {
+			return notAllowed()
+		}
+		rawParams.Query = query
+		return nil
+	}
+
+	hash := Hash(rawParams.Query)
+	if _, ok := a.Manifest[hash]; !ok || (sentHash != "" && sentHash != hash) {
+		return notAllowed()
+	}
+	return nil
+}
+
+func notAllowed() *gqlerror.Error {
+	err := gqlerror.Errorf("Operation is not in the persisted query allowlist")
+	errcode.Set(err, CodeNotAllowed)
+	return err
+}
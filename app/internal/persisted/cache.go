@@ -0,0 +1,95 @@
+package persisted
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	redisKeyPrefix = "apq:"
+	redisIndexKey  = redisKeyPrefix + "index"
+)
+
+// addScript stores a query and records it in an index ordered by the time
+// it was added, evicting the oldest queries beyond the given number. Any
+// client can register a query, so without the bound the key space would
+// grow with every distinct one until the TTL ran out.
+var addScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local maxEntries = tonumber(ARGV[4])
+
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+redis.call('ZADD', KEYS[2], now, KEYS[1])
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', now - ttl)
+
+local excess = redis.call('ZCARD', KEYS[2]) - maxEntries
+if excess > 0 then
+	local evicted = redis.call('ZRANGE', KEYS[2], 0, excess - 1)
+	redis.call('ZREMRANGEBYRANK', KEYS[2], 0, excess - 1)
+	redis.call('DEL', unpack(evicted))
+end
+
+redis.call('PEXPIRE', KEYS[2], ttl)
+return math.max(excess, 0)
+`)
+
+// RedisCache stores automatic persisted queries in Redis, so that a hash
+// registered through one replica resolves on all of them. It holds at most
+// maxEntries queries of at most maxQuerySize bytes each, since Redis is
+// shared with pub/sub and rate limiting. A query that is not stored or a
+// Redis failure reads as a miss, which makes the client send the full
+// query again.
+type RedisCache struct {
+	client       *redis.Client
+	ttl          time.Duration
+	maxEntries   int
+	maxQuerySize int
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+var _ graphql.Cache[string] = &RedisCache{}
+
+func NewRedisCache(client *redis.Client, ttl time.Duration, maxEntries int, maxQuerySize int, logger *slog.Logger) *RedisCache {
+	return &RedisCache{
+		client:       client,
+		ttl:          ttl,
+		maxEntries:   maxEntries,
+		maxQuerySize: maxQuerySize,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool) {
+	query, err := c.client.Get(ctx, redisKeyPrefix+key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			c.logger.WarnContext(ctx, "Failed to read persisted query", "hash", key, "error", err)
+		}
+		return "", false
+	}
+	return query, true
+}
+
+func (c *RedisCache) Add(ctx context.Context, key string, value string) {
+	if len(value) > c.maxQuerySize {
+		c.logger.DebugContext(ctx, "Persisted query too large to store", "hash", key, "size", len(value))
+		return
+	}
+
+	err := addScript.Run(ctx, c.client, []string{redisKeyPrefix + key, redisIndexKey},
+		value,
+		max(c.ttl.Milliseconds(), 1),
+		c.now().UnixMilli(),
+		c.maxEntries,
+	).Err()
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to store persisted query", "hash", key, "error", err)
+	}
+}
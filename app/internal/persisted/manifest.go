@@ -0,0 +1,193 @@
+package persisted
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Manifest maps the SHA-256 hash of every trusted document to its text.
+type Manifest map[string]string
+
+// Hash returns the hash under which query is stored, the same one clients
+// send in extensions.persistedQuery.sha256Hash.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	for hash, query := range manifest {
+		if Hash(query) != hash {
+			return nil, fmt.Errorf("manifest %s: hash %s does not match its document", path, hash)
+		}
+	}
+	return manifest, nil
+}
+
+func (m Manifest) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m)
+}
+
+// OperationNames returns the names of the operations in the manifest.
+func (m Manifest) OperationNames() []string {
+	var names []string
+	for _, query := range m {
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		if err != nil {
+			continue
+		}
+		for _, operation := range doc.Operations {
+			if operation.Name != "" {
+				names = append(names, operation.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Extract turns every operation found in sources into a standalone document
+// holding the operation and the fragments it uses, which may be defined in
+// any of the sources. Documents keep the text they were written in, so that
+// clients hash exactly what they send. Documents are checked against schema.
+func Extract(schema *ast.Schema, sources ...*ast.Source) (Manifest, error) {
+	var operations ast.OperationList
+	fragments := make(map[string]*ast.FragmentDefinition)
+	texts := make(map[*ast.Position]string)
+	definitions := make(map[*ast.Source]int)
+
+	for _, source := range sources {
+		doc, err := parser.ParseQuery(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", source.Name, err)
+		}
+
+		operations = append(operations, doc.Operations...)
+		for _, fragment := range doc.Fragments {
+			if _, exists := fragments[fragment.Name]; exists {
+				return nil, fmt.Errorf("%s: fragment %s is defined more than once", source.Name, fragment.Name)
+			}
+			fragments[fragment.Name] = fragment
+		}
+		for pos, text := range definitionTexts(source, doc) {
+			texts[pos] = text
+		}
+		definitions[source] = len(doc.Operations) + len(doc.Fragments)
+	}
+
+	manifest := make(Manifest, len(operations))
+	for _, operation := range operations {
+		used := make(map[string]*ast.FragmentDefinition)
+		if err := collectFragments(operation.SelectionSet, fragments, used); err != nil {
+			return nil, fmt.Errorf("operation %s: %w", operation.Name, err)
+		}
+		query := standalone(operation, used, texts, definitions)
+
+		if _, errs := gqlparser.LoadQuery(schema, query); errs != nil {
+			return nil, fmt.Errorf("operation %s (%s) is invalid: %w", operation.Name, operation.Position.Src.Name, errs)
+		}
+		manifest[Hash(query)] = query
+	}
+	return manifest, nil
+}
+
+// definitionTexts returns the text of every definition in doc, keyed by its
+// position and running up to where the next definition starts.
+func definitionTexts(source *ast.Source, doc *ast.QueryDocument) map[*ast.Position]string {
+	var positions []*ast.Position
+	for _, operation := range doc.Operations {
+		positions = append(positions, operation.Position)
+	}
+	for _, fragment := range doc.Fragments {
+		positions = append(positions, fragment.Position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Start < positions[j].Start
+	})
+
+	// Positions count runes, not bytes.
+	input := []rune(source.Input)
+	texts := make(map[*ast.Position]string, len(positions))
+	for i, pos := range positions {
+		end := len(input)
+		if i+1 < len(positions) {
+			end = positions[i+1].Start
+		}
+		texts[pos] = strings.TrimSpace(string(input[pos.Start:end]))
+	}
+	return texts
+}
+
+// standalone returns a file holding nothing but the operation and the
+// fragments it uses as it is, and otherwise joins their texts, the operation
+// first and the fragments by name.
+func standalone(operation *ast.OperationDefinition, used map[string]*ast.FragmentDefinition, texts map[*ast.Position]string, definitions map[*ast.Source]int) string {
+	source := operation.Position.Src
+	whole := definitions[source] == 1+len(used)
+
+	names := make([]string, 0, len(used))
+	for name, fragment := range used {
+		names = append(names, name)
+		whole = whole && fragment.Position.Src == source
+	}
+	if whole {
+		return source.Input
+	}
+	sort.Strings(names)
+
+	parts := []string{texts[operation.Position]}
+	for _, name := range names {
+		parts = append(parts, texts[used[name].Position])
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func collectFragments(set ast.SelectionSet, fragments, used map[string]*ast.FragmentDefinition) error {
+	for _, selection := range set {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if err := collectFragments(s.SelectionSet, fragments, used); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			if err := collectFragments(s.SelectionSet, fragments, used); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			if _, seen := used[s.Name]; seen {
+				continue
+			}
+			fragment, ok := fragments[s.Name]
+			if !ok {
+				return fmt.Errorf("unknown fragment %s", s.Name)
+			}
+			used[s.Name] = fragment
+			if err := collectFragments(fragment.SelectionSet, fragments, used); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
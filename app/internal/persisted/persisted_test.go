@@ -0,0 +1,239 @@
+package persisted
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var testSchema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphqls", Input: `
+type Query {
+  post(id: ID!): Post!
+}
+
+type Post {
+  id: ID!
+  title: String!
+  user: User!
+}
+
+type User {
+  id: ID!
+  username: String!
+}
+`})
+
+func TestExtract(t *testing.T) {
+	t.Run("operations with shared fragments", func(t *testing.T) {
+		manifest, err := Extract(testSchema,
+			&ast.Source{Name: "post.graphql", Input: `
+query GetPost($id: ID!) { post(id: $id) { ...PostFields } }
+query GetTitle($id: ID!) { post(id: $id) { title } }
+`},
+			&ast.Source{Name: "fragments.graphql", Input: `
+fragment PostFields on Post { id title user { ...UserFields } }
+fragment UserFields on User { id username }
+fragment Unused on User { id }
+`},
+		)
+		require.NoError(t, err)
+		require.Len(t, manifest, 2)
+		assert.Equal(t, []string{"GetPost", "GetTitle"}, manifest.OperationNames())
+
+		for hash, query := range manifest {
+			assert.Equal(t, Hash(query), hash)
+			assert.NotContains(t, query, "Unused")
+			if strings.Contains(query, "GetPost") {
+				assert.Contains(t, query, "fragment PostFields")
+				assert.Contains(t, query, "fragment UserFields")
+			}
+		}
+	})
+
+	t.Run("hash of source file is accepted", func(t *testing.T) {
+		input := `# Post page
+query GetPost($id: ID!) {
+  post(id: $id) {
+    ...PostFields
+  }
+}
+
+fragment PostFields on Post { id title }
+`
+		manifest, err := Extract(testSchema, &ast.Source{Name: "post.graphql", Input: input})
+		require.NoError(t, err)
+		assert.Equal(t, Manifest{Hash(input): input}, manifest)
+
+		params := &graphql.RawParams{Extensions: map[string]interface{}{
+			"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": Hash(input)},
+		}}
+		assert.Nil(t, Allowlist{Manifest: manifest}.MutateOperationParameters(context.Background(), params))
+		assert.Equal(t, input, params.Query)
+	})
+
+	t.Run("original text of shared definitions", func(t *testing.T) {
+		manifest, err := Extract(testSchema,
+			&ast.Source{Name: "post.graphql", Input: "query GetPost($id: ID!) { post(id: $id) { ...PostFields } }\nquery GetTitle($id: ID!) { post(id: $id) { title } }\n"},
+			&ast.Source{Name: "fragments.graphql", Input: "fragment PostFields on Post {   id   title }\n"},
+		)
+		require.NoError(t, err)
+
+		getPost := "query GetPost($id: ID!) { post(id: $id) { ...PostFields } }\n\nfragment PostFields on Post {   id   title }"
+		assert.Equal(t, getPost, manifest[Hash(getPost)])
+		getTitle := "query GetTitle($id: ID!) { post(id: $id) { title } }"
+		assert.Equal(t, getTitle, manifest[Hash(getTitle)])
+	})
+
+	t.Run("unknown fragment", func(t *testing.T) {
+		_, err := Extract(testSchema, &ast.Source{Name: "a.graphql", Input: `query A { post(id: "1") { ...Missing } }`})
+		assert.ErrorContains(t, err, "unknown fragment Missing")
+	})
+
+	t.Run("invalid against schema", func(t *testing.T) {
+		_, err := Extract(testSchema, &ast.Source{Name: "a.graphql", Input: `query A { post(id: "1") { body } }`})
+		assert.ErrorContains(t, err, "operation A (a.graphql) is invalid")
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		_, err := Extract(testSchema, &ast.Source{Name: "a.graphql", Input: `query A { post(`})
+		assert.ErrorContains(t, err, "failed to parse a.graphql")
+	})
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	query := `query A { post(id: "1") { id } }`
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(dir, "ok.json")
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, Manifest{Hash(query): query}.Write(file))
+		require.NoError(t, file.Close())
+
+		manifest, err := LoadManifest(path)
+		require.NoError(t, err)
+		assert.Equal(t, Manifest{Hash(query): query}, manifest)
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"abc": "query A { post(id: \"1\") { id } }"}`), 0o644))
+
+		_, err := LoadManifest(path)
+		assert.ErrorContains(t, err, "does not match")
+	})
+}
+
+func TestAllowlist(t *testing.T) {
+	ctx := context.Background()
+	query := `query A { post(id: "1") { id } }`
+	allowlist := Allowlist{Manifest: Manifest{Hash(query): query}}
+	persistedQuery := func(hash string) map[string]interface{} {
+		return map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": hash}}
+	}
+
+	t.Run("known hash", func(t *testing.T) {
+		params := &graphql.RawParams{Extensions: persistedQuery(Hash(query))}
+		assert.Nil(t, allowlist.MutateOperationParameters(ctx, params))
+		assert.Equal(t, query, params.Query)
+	})
+
+	t.Run("known document", func(t *testing.T) {
+		assert.Nil(t, allowlist.MutateOperationParameters(ctx, &graphql.RawParams{Query: query}))
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		err := allowlist.MutateOperationParameters(ctx, &graphql.RawParams{Extensions: persistedQuery("abc")})
+		require.NotNil(t, err)
+		assert.Equal(t, CodeNotAllowed, err.Extensions["code"])
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		err := allowlist.MutateOperationParameters(ctx, &graphql.RawParams{Query: `{ post(id: "1") { title } }`})
+		require.NotNil(t, err)
+		assert.Equal(t, CodeNotAllowed, err.Extensions["code"])
+	})
+
+	t.Run("document with another hash", func(t *testing.T) {
+		err := allowlist.MutateOperationParameters(ctx, &graphql.RawParams{Query: query, Extensions: persistedQuery("abc")})
+		require.NotNil(t, err)
+		assert.Equal(t, CodeNotAllowed, err.Extensions["code"])
+	})
+}
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	now := time.UnixMilli(1700000000000)
+
+	t.Run("add and get", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		cache := NewRedisCache(client, time.Hour, 100, 1024, logger)
+		cache.now = func() time.Time { return now }
+
+		mock.ExpectEvalSha(addScript.Hash(), []string{"apq:abc", "apq:index"}, "{ id }", int64(3600000), now.UnixMilli(), 100).
+			SetVal(int64(0))
+		mock.ExpectGet("apq:abc").SetVal("{ id }")
+
+		cache.Add(ctx, "abc", "{ id }")
+		query, ok := cache.Get(ctx, "abc")
+		assert.True(t, ok)
+		assert.Equal(t, "{ id }", query)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query too large is not stored", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		cache := NewRedisCache(client, time.Hour, 100, 8, logger)
+
+		cache.Add(ctx, "abc", "{ id title }")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("store error is only logged", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		cache := NewRedisCache(client, time.Hour, 100, 1024, logger)
+		cache.now = func() time.Time { return now }
+
+		mock.ExpectEvalSha(addScript.Hash(), []string{"apq:abc", "apq:index"}, "{ id }", int64(3600000), now.UnixMilli(), 100).
+			SetErr(errors.New("OOM command not allowed"))
+
+		cache.Add(ctx, "abc", "{ id }")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		cache := NewRedisCache(client, time.Hour, 100, 1024, logger)
+
+		mock.ExpectGet("apq:abc").RedisNil()
+
+		_, ok := cache.Get(ctx, "abc")
+		assert.False(t, ok)
+	})
+
+	t.Run("redis error reads as miss", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		cache := NewRedisCache(client, time.Hour, 100, 1024, logger)
+
+		mock.ExpectGet("apq:abc").SetErr(errors.New("connection refused"))
+
+		_, ok := cache.Get(ctx, "abc")
+		assert.False(t, ok)
+	})
+}
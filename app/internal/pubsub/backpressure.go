@@ -0,0 +1,117 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what gives way when an event arrives for a
+// subscriber whose buffer is full.
+type OverflowPolicy string
+
+const (
+	// DropOldest discards the oldest buffered event to make room.
+	DropOldest OverflowPolicy = "drop-oldest"
+	// DropNewest discards the arriving event.
+	DropNewest OverflowPolicy = "drop-newest"
+	// Disconnect ends the subscription, so that the client resubscribes
+	// and re-queries instead of reading a stream with gaps.
+	Disconnect OverflowPolicy = "disconnect"
+)
+
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(s); policy {
+	case DropOldest, DropNewest, Disconnect:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy: %s", s)
+	}
+}
+
+// Backpressure configures how events are buffered for every subscriber.
+type Backpressure struct {
+	BufferSize int
+	Policy     OverflowPolicy
+}
+
+// Lag records what a subscriber missed. The transport puts one into the
+// context of a subscription to tell the client about skipped events.
+type Lag struct {
+	skipped      atomic.Uint64
+	disconnected atomic.Bool
+}
+
+type lagKey struct{}
+
+func WithLag(ctx context.Context, lag *Lag) context.Context {
+	return context.WithValue(ctx, lagKey{}, lag)
+}
+
+func lagFromContext(ctx context.Context) *Lag {
+	lag, _ := ctx.Value(lagKey{}).(*Lag)
+	return lag
+}
+
+// TakeSkipped returns the number of events skipped since the previous call.
+func (l *Lag) TakeSkipped() uint64 {
+	return l.skipped.Swap(0)
+}
+
+// Disconnected reports whether the subscription was ended for falling behind.
+func (l *Lag) Disconnected() bool {
+	return l.disconnected.Load()
+}
+
+func (l *Lag) skip(n uint64) {
+	if l != nil {
+		l.skipped.Add(n)
+	}
+}
+
+func (l *Lag) disconnect() {
+	if l != nil {
+		l.skipped.Add(1)
+		l.disconnected.Store(true)
+	}
+}
+
+// Send hands value to the subscriber reading from out without ever blocking
+// the publisher side. When the buffer of out is full, policy decides what
+// gives way and the Lag in ctx, if any, learns about it. Send returns the
+// number of events dropped and false once the subscriber has to be
+// disconnected.
+func Send[T any](ctx context.Context, out chan *T, value *T, policy OverflowPolicy) (int, bool) {
+	select {
+	case out <- value:
+		return 0, true
+	default:
+	}
+
+	lag := lagFromContext(ctx)
+	switch policy {
+	case DropNewest:
+		lag.skip(1)
+		return 1, true
+	case DropOldest:
+		dropped := 0
+		for {
+			select {
+			case out <- value:
+				return dropped, true
+			default:
+			}
+			// The subscriber may have caught up in the meantime, in which
+			// case there is nothing to drop and the next send succeeds.
+			select {
+			case <-out:
+				dropped++
+				lag.skip(1)
+			default:
+			}
+		}
+	default:
+		lag.disconnect()
+		return 1, false
+	}
+}
@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		policy       OverflowPolicy
+		received     []int
+		skipped      uint64
+		disconnected bool
+	}{
+		{DropOldest, []int{4, 5}, 3, false},
+		{DropNewest, []int{1, 2}, 3, false},
+		{Disconnect, []int{1, 2}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			lag := &Lag{}
+			ctx := WithLag(context.Background(), lag)
+			out := make(chan *int, 2)
+
+			for _, value := range values {
+				if _, ok := Send(ctx, out, &value, tt.policy); !ok {
+					break
+				}
+			}
+			close(out)
+
+			var received []int
+			for value := range out {
+				received = append(received, *value)
+			}
+			assert.Equal(t, tt.received, received)
+			assert.Equal(t, tt.skipped, lag.TakeSkipped())
+			assert.Equal(t, uint64(0), lag.TakeSkipped())
+			assert.Equal(t, tt.disconnected, lag.Disconnected())
+		})
+	}
+}
+
+func TestSendWithoutLag(t *testing.T) {
+	out := make(chan *int, 1)
+	value := 1
+
+	dropped, ok := Send(context.Background(), out, &value, DropNewest)
+	assert.Equal(t, 0, dropped)
+	assert.True(t, ok)
+
+	dropped, ok = Send(context.Background(), out, &value, DropNewest)
+	assert.Equal(t, 1, dropped)
+	assert.True(t, ok)
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	policy, err := ParseOverflowPolicy("drop-newest")
+	assert.NoError(t, err)
+	assert.Equal(t, DropNewest, policy)
+
+	_, err = ParseOverflowPolicy("block")
+	assert.Error(t, err)
+}
@@ -50,6 +50,20 @@ func (mr *MockPubSubClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPubSubClient)(nil).Close))
 }
 
+// Ping mocks base method.
+func (m *MockPubSubClient) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockPubSubClientMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockPubSubClient)(nil).Ping), ctx)
+}
+
 // PublishComment mocks base method.
 func (m *MockPubSubClient) PublishComment(ctx context.Context, postId uuid.UUID, comment *model.Comment) error {
 	m.ctrl.T.Helper()
@@ -64,17 +78,46 @@ func (mr *MockPubSubClientMockRecorder) PublishComment(ctx, postId, comment inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishComment", reflect.TypeOf((*MockPubSubClient)(nil).PublishComment), ctx, postId, comment)
 }
 
+// PublishPost mocks base method.
+func (m *MockPubSubClient) PublishPost(ctx context.Context, post *model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishPost", ctx, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishPost indicates an expected call of PublishPost.
+func (mr *MockPubSubClientMockRecorder) PublishPost(ctx, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishPost", reflect.TypeOf((*MockPubSubClient)(nil).PublishPost), ctx, post)
+}
+
 // SubscribeOnComments mocks base method.
-func (m *MockPubSubClient) SubscribeOnComments(ctx context.Context, postID uuid.UUID) (<-chan *model.Comment, error) {
+func (m *MockPubSubClient) SubscribeOnComments(ctx context.Context, postId uuid.UUID) (<-chan *model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubscribeOnComments", ctx, postID)
+	ret := m.ctrl.Call(m, "SubscribeOnComments", ctx, postId)
 	ret0, _ := ret[0].(<-chan *model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SubscribeOnComments indicates an expected call of SubscribeOnComments.
-func (mr *MockPubSubClientMockRecorder) SubscribeOnComments(ctx, postID interface{}) *gomock.Call {
+func (mr *MockPubSubClientMockRecorder) SubscribeOnComments(ctx, postId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOnComments", reflect.TypeOf((*MockPubSubClient)(nil).SubscribeOnComments), ctx, postId)
+}
+
+// SubscribeOnPosts mocks base method.
+func (m *MockPubSubClient) SubscribeOnPosts(ctx context.Context) (<-chan *model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeOnPosts", ctx)
+	ret0, _ := ret[0].(<-chan *model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeOnPosts indicates an expected call of SubscribeOnPosts.
+func (mr *MockPubSubClientMockRecorder) SubscribeOnPosts(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOnComments", reflect.TypeOf((*MockPubSubClient)(nil).SubscribeOnComments), ctx, postID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOnPosts", reflect.TypeOf((*MockPubSubClient)(nil).SubscribeOnPosts), ctx)
 }
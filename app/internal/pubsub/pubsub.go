@@ -14,5 +14,19 @@ type PubSubClient interface {
 
 	SubscribeOnComments(ctx context.Context, postId uuid.UUID) (<-chan *model.Comment, error)
 
+	PublishPost(ctx context.Context, post *model.Post) error
+
+	SubscribeOnPosts(ctx context.Context) (<-chan *model.Post, error)
+
+	Ping(ctx context.Context) error
+
 	Close() error
 }
+
+// CommentStats counts comments that went through this process: published to
+// the broker, delivered to a subscriber, or dropped on the way to one.
+type CommentStats struct {
+	Published uint64
+	Delivered uint64
+	Dropped   uint64
+}
@@ -2,131 +2,447 @@ package pubsub_redis
 
 import (
 	"app/graph/model"
+	"app/internal/pubsub"
+	"app/internal/tracing"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	postsChannel          = "posts:published"
+	commentsChannelPrefix = "comments:"
+)
+
+// traceKey is the field added to a published value for the trace context of
+// its publisher. The value itself stays at the top level, so that replicas
+// unaware of the field decode it like any other unknown one.
+const traceKey = "_trace"
+
+// message is read from every payload before the value itself.
+type message struct {
+	Trace map[string]string `json:"_trace"`
+
+	// Envelope of replicas that wrapped the value instead.
+	EnvelopeTrace map[string]string `json:"trace"`
+	Data          json.RawMessage   `json:"data"`
+}
+
+// upstreamBufferSize is the number of messages go-redis buffers between the
+// connection and the dispatcher.
+const upstreamBufferSize = 1000
+
+// upstreamTimeout bounds every subscribe and unsubscribe call made on the
+// shared connection.
+const upstreamTimeout = 5 * time.Second
+
+var errClosed = errors.New("pubsub is closed")
+
+// deliveryCounters tracks what happened to messages of one kind.
+type deliveryCounters struct {
+	published atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// RedisPubSub holds a single Redis connection for all subscriptions of the
+// process. The first local subscriber of a channel subscribes the connection
+// to it and the last one unsubscribes it; in between every message is
+// decoded once and fanned out to the local subscribers in memory.
 type RedisPubSub struct {
-	client *redis.Client
-	mu     sync.RWMutex
-	subs   map[uuid.UUID]map[*redis.PubSub]struct{}
+	client       *redis.Client
+	backpressure pubsub.Backpressure
+	logger       *slog.Logger
+	comments     deliveryCounters
+	posts        deliveryCounters
+
+	// mu guards everything below, including the subscribers of every topic.
+	// Calls to Redis are made without it.
+	mu       sync.Mutex
+	upstream *redis.PubSub
+	topics   map[string]*topicEntry
+	closed   bool
+}
+
+// upstreamState is the state of the subscription of the shared connection
+// to one channel.
+type upstreamState int
+
+const (
+	// subscribing waits for Redis to confirm the subscription.
+	subscribing upstreamState = iota
+	subscribed
+	// unsubscribing waits for the unsubscribe command to be sent.
+	unsubscribing
+)
+
+// topicEntry is the topic of a channel together with the subscription of
+// the shared connection to it.
+type topicEntry struct {
+	topic fanout
+	state upstreamState
+	// settled is closed once state leaves subscribing or unsubscribing.
+	settled chan struct{}
+	// err is why subscribing failed.
+	err error
 }
 
-func NewRedisPubSub(client *redis.Client) *RedisPubSub {
+func NewRedisPubSub(client *redis.Client, backpressure pubsub.Backpressure, logger *slog.Logger) *RedisPubSub {
+	backpressure.BufferSize = max(backpressure.BufferSize, 1)
 	return &RedisPubSub{
-		client: client,
-		subs:   make(map[uuid.UUID]map[*redis.PubSub]struct{}),
+		client:       client,
+		backpressure: backpressure,
+		logger:       logger,
+		topics:       make(map[string]*topicEntry),
 	}
 }
 
-func (r *RedisPubSub) PublishComment(ctx context.Context, postID uuid.UUID, comment *model.Comment) error {
-	payload, err := json.Marshal(comment)
+func (r *RedisPubSub) PublishComment(ctx context.Context, postID uuid.UUID, comment *model.Comment) (err error) {
+	channel := r.getChannel(postID)
+	ctx, span := startSpan(ctx, "publish", channel, trace.SpanKindProducer)
+	defer func() { tracing.End(span, err) }()
+
+	payload, err := encodeMessage(ctx, comment)
 	if err != nil {
 		return fmt.Errorf("failed to marshal comment: %w", err)
 	}
 
-	channel := r.getChannel(postID)
 	if err := r.client.Publish(ctx, channel, payload).Err(); err != nil {
 		return fmt.Errorf("failed to publish comment: %w", err)
 	}
+	r.comments.published.Add(1)
 
-	log.Printf("Published comment to channel %s", channel)
+	r.logger.DebugContext(ctx, "Published comment", "channel", channel)
 	return nil
 }
 
 func (r *RedisPubSub) SubscribeOnComments(ctx context.Context, postID uuid.UUID) (<-chan *model.Comment, error) {
-	channel := r.getChannel(postID)
-	pubsub := r.client.Subscribe(ctx, channel)
+	return subscribe[model.Comment](ctx, r, r.getChannel(postID), &r.comments)
+}
+
+func (r *RedisPubSub) PublishPost(ctx context.Context, post *model.Post) (err error) {
+	ctx, span := startSpan(ctx, "publish", postsChannel, trace.SpanKindProducer)
+	defer func() { tracing.End(span, err) }()
+
+	payload, err := encodeMessage(ctx, post)
+	if err != nil {
+		return fmt.Errorf("failed to marshal post: %w", err)
+	}
+
+	if err := r.client.Publish(ctx, postsChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish post: %w", err)
+	}
+	r.posts.published.Add(1)
+
+	r.logger.DebugContext(ctx, "Published post", "channel", postsChannel)
+	return nil
+}
+
+func (r *RedisPubSub) SubscribeOnPosts(ctx context.Context) (<-chan *model.Post, error) {
+	return subscribe[model.Post](ctx, r, postsChannel, &r.posts)
+}
 
+// subscribe adds a local subscriber to channel, subscribing the connection
+// to it first if nobody in the process listens to it yet. The subscriber is
+// removed once ctx is done.
+func subscribe[T any](ctx context.Context, r *RedisPubSub, channel string, counters *deliveryCounters) (<-chan *T, error) {
 	r.mu.Lock()
-	if _, exists := r.subs[postID]; !exists {
-		r.subs[postID] = make(map[*redis.PubSub]struct{})
+	e, err := r.join(ctx, channel)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
 	}
-	r.subs[postID][pubsub] = struct{}{}
-	r.mu.Unlock()
 
-	if _, err := pubsub.Receive(ctx); err != nil {
-		r.removeSubscription(postID, pubsub)
-		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	var upstream *redis.PubSub
+	if e == nil {
+		e = &topicEntry{
+			topic:   newTopic[T](channel, r.backpressure.Policy, counters, r.logger),
+			state:   subscribing,
+			settled: make(chan struct{}),
+		}
+		r.topics[channel] = e
+		upstream = r.connect()
 	}
+	t := e.topic.(*topic[T])
 
-	commentChan := make(chan *model.Comment)
+	sub := &subscriber[T]{ctx: ctx, out: make(chan *T, r.backpressure.BufferSize)}
+	t.add(sub)
+	sub.stop = context.AfterFunc(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
 
-	go r.listenForMessages(ctx, postID, pubsub, commentChan)
+		if t.remove(sub) && t.size() == 0 {
+			r.logger.DebugContext(ctx, "Subscription canceled", "channel", channel)
+			r.release(channel, e)
+		}
+	})
+
+	if e.state == subscribed {
+		r.mu.Unlock()
+		return sub.out, nil
+	}
+	settled := e.settled
+	r.mu.Unlock()
 
-	return commentChan, nil
+	if upstream != nil {
+		r.subscribeUpstream(ctx, upstream, channel, e, settled)
+	}
+	<-settled
+	if e.err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", e.err)
+	}
+	return sub.out, nil
 }
 
-func (r *RedisPubSub) listenForMessages(ctx context.Context, postID uuid.UUID, pubsub *redis.PubSub, commentChan chan<- *model.Comment) {
-	defer func() {
-		r.removeSubscription(postID, pubsub)
-		close(commentChan)
-	}()
+// join returns the entry of channel for a new subscriber, or nil if there is
+// none yet. If the connection is being unsubscribed from channel, join waits
+// for that to be sent first, so that the new subscription is sent after it.
+// r.mu must be held and is released while waiting.
+func (r *RedisPubSub) join(ctx context.Context, channel string) (*topicEntry, error) {
+	for {
+		if r.closed {
+			return nil, errClosed
+		}
 
-	redisChan := pubsub.Channel(redis.WithChannelSize(100))
+		e, exists := r.topics[channel]
+		if !exists {
+			return nil, nil
+		}
+		if e.state != unsubscribing {
+			return e, nil
+		}
 
-	for {
+		settled := e.settled
+		r.mu.Unlock()
 		select {
+		case <-settled:
 		case <-ctx.Done():
-			log.Printf("Subscription canceled for post %s", postID)
+			r.mu.Lock()
+			return nil, ctx.Err()
+		}
+		r.mu.Lock()
+	}
+}
+
+// connect returns the shared connection, starting its dispatcher on first
+// use. go-redis dials on the dispatcher's goroutine, so connect makes no
+// network calls itself. r.mu must be held.
+func (r *RedisPubSub) connect() *redis.PubSub {
+	if r.upstream == nil {
+		r.upstream = r.client.Subscribe(context.Background())
+		go r.dispatch(r.upstream.ChannelWithSubscriptions(context.Background(), upstreamBufferSize))
+	}
+	return r.upstream
+}
+
+// subscribeUpstream subscribes the connection to channel and waits for Redis
+// to confirm it through the dispatcher. e fails if that does not happen
+// within upstreamTimeout.
+func (r *RedisPubSub) subscribeUpstream(ctx context.Context, upstream *redis.PubSub, channel string, e *topicEntry, settled <-chan struct{}) {
+	// Later subscribers share the subscription, so it must not depend on
+	// the first one staying.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), upstreamTimeout)
+	defer cancel()
+
+	err := upstream.Subscribe(ctx, channel)
+	if err == nil {
+		select {
+		case <-settled:
 			return
-		case msg, ok := <-redisChan:
-			if !ok {
-				log.Printf("Redis channel closed for post %s", postID)
-				return
-			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
 
-			var comment model.Comment
-			if err := json.Unmarshal([]byte(msg.Payload), &comment); err != nil {
-				log.Printf("Failed to unmarshal comment: %v", err)
-				continue
-			}
+	r.mu.Lock()
+	failed := r.settle(channel, e, err)
+	r.mu.Unlock()
+	if !failed {
+		return
+	}
 
-			select {
-			case commentChan <- &comment:
-			case <-time.After(5 * time.Second):
-				log.Println("Timeout sending comment to channel")
-			case <-ctx.Done():
-				return
+	// go-redis tracks the channel even if subscribing failed and would
+	// subscribe to it again after a reconnect.
+	ctx, cancel = context.WithTimeout(context.Background(), upstreamTimeout)
+	defer cancel()
+	if err := upstream.Unsubscribe(ctx, channel); err != nil && !errors.Is(err, redis.ErrClosed) {
+		r.logger.Warn("Failed to unsubscribe", "channel", channel, "error", err)
+	}
+}
+
+// settle ends the subscribing state of e, failing it with err unless err is
+// nil, and reports whether e failed. r.mu must be held.
+func (r *RedisPubSub) settle(channel string, e *topicEntry, err error) bool {
+	if r.topics[channel] != e || e.state != subscribing {
+		return false
+	}
+
+	if err != nil {
+		e.err = err
+		delete(r.topics, channel)
+		e.topic.close()
+		close(e.settled)
+		return true
+	}
+
+	e.state = subscribed
+	close(e.settled)
+	// Every subscriber may have left while waiting.
+	r.release(channel, e)
+	return false
+}
+
+// release starts unsubscribing the connection from channel once e has no
+// subscribers left. r.mu must be held.
+func (r *RedisPubSub) release(channel string, e *topicEntry) {
+	if e.topic.size() > 0 || e.state != subscribed || r.topics[channel] != e {
+		return
+	}
+
+	e.state = unsubscribing
+	e.settled = make(chan struct{})
+	go r.unsubscribeUpstream(r.upstream, channel, e)
+}
+
+// unsubscribeUpstream unsubscribes the connection from channel and then
+// forgets e.
+func (r *RedisPubSub) unsubscribeUpstream(upstream *redis.PubSub, channel string, e *topicEntry) {
+	if upstream != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), upstreamTimeout)
+		err := upstream.Unsubscribe(ctx, channel)
+		cancel()
+		if err != nil && !errors.Is(err, redis.ErrClosed) {
+			r.logger.Warn("Failed to unsubscribe", "channel", channel, "error", err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.topics[channel] == e {
+		delete(r.topics, channel)
+	}
+	close(e.settled)
+}
+
+// dispatch fans every message out to the subscribers of its channel until
+// the connection is closed. go-redis reconnects and resubscribes on its own.
+func (r *RedisPubSub) dispatch(messages <-chan any) {
+	for msg := range messages {
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			if msg.Kind == "subscribe" {
+				r.confirm(msg.Channel)
 			}
+		case *redis.Message:
+			r.deliver(msg.Channel, msg.Payload)
 		}
 	}
+	r.logger.Info("Redis connection closed")
 }
 
-func (r *RedisPubSub) removeSubscription(postId uuid.UUID, pubsub *redis.PubSub) {
+func (r *RedisPubSub) confirm(channel string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if subs, exists := r.subs[postId]; exists {
-		delete(subs, pubsub)
-		if len(subs) == 0 {
-			delete(r.subs, postId)
-		}
+	if e, exists := r.topics[channel]; exists {
+		r.settle(channel, e, nil)
 	}
-	pubsub.Close()
 }
 
-func (r *RedisPubSub) Close() error {
+func (r *RedisPubSub) deliver(channel string, payload string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var err error
-	for _, subs := range r.subs {
-		for pubsub := range subs {
-			if e := pubsub.Close(); e != nil && err == nil {
-				err = e
-			}
+	e, exists := r.topics[channel]
+	if !exists || e.state != subscribed {
+		return
+	}
+	e.topic.dispatch(payload)
+	// Every subscriber may have been disconnected for falling behind.
+	r.release(channel, e)
+}
+
+func encodeMessage(ctx context.Context, value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	carrier := tracing.Inject(ctx)
+	if len(carrier) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields[traceKey], err = json.Marshal(carrier); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// decodeMessage fills value from payload and returns ctx carrying the
+// publisher's trace context, if the payload has one.
+func decodeMessage(ctx context.Context, payload string, value any) (context.Context, error) {
+	var msg message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		return ctx, err
+	}
+
+	if msg.Trace == nil && msg.Data != nil {
+		return tracing.Extract(ctx, msg.EnvelopeTrace), json.Unmarshal(msg.Data, value)
+	}
+	return tracing.Extract(ctx, msg.Trace), json.Unmarshal([]byte(payload), value)
+}
+
+func startSpan(ctx context.Context, operation string, channel string, kind trace.SpanKind) (context.Context, trace.Span) {
+	return tracing.Start(ctx, operation+" "+channel,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "redis"),
+			attribute.String("messaging.operation.name", operation),
+			attribute.String("messaging.destination.name", channel),
+		),
+	)
+}
+
+func (r *RedisPubSub) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close ends all subscriptions and closes the connections to Redis.
+func (r *RedisPubSub) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	for _, e := range r.topics {
+		e.topic.close()
+		if e.state == subscribing {
+			e.err = errClosed
+			close(e.settled)
 		}
 	}
-	r.subs = make(map[uuid.UUID]map[*redis.PubSub]struct{})
+	r.topics = make(map[string]*topicEntry)
+	upstream := r.upstream
+	r.mu.Unlock()
 
+	var err error
+	if upstream != nil {
+		err = upstream.Close()
+	}
 	if e := r.client.Close(); e != nil && err == nil {
 		err = e
 	}
@@ -134,6 +450,31 @@ func (r *RedisPubSub) Close() error {
 	return err
 }
 
+// CommentStats reports comment traffic since the process started.
+func (r *RedisPubSub) CommentStats() pubsub.CommentStats {
+	return pubsub.CommentStats{
+		Published: r.comments.published.Load(),
+		Delivered: r.comments.delivered.Load(),
+		Dropped:   r.comments.dropped.Load(),
+	}
+}
+
+// SubscriptionsPerPost returns the number of active comment subscriptions
+// held by this process for every post that has any.
+func (r *RedisPubSub) SubscriptionsPerPost() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]int)
+	for channel, e := range r.topics {
+		postId, ok := strings.CutPrefix(channel, commentsChannelPrefix)
+		if n := e.topic.size(); ok && n > 0 {
+			counts[postId] = n
+		}
+	}
+	return counts
+}
+
 func (r *RedisPubSub) getChannel(postID uuid.UUID) string {
-	return fmt.Sprintf("comments:%s", postID.String())
+	return commentsChannelPrefix + postID.String()
 }
@@ -2,15 +2,26 @@ package pubsub_redis
 
 import (
 	"app/graph/model"
+	"app/internal/pubsub"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"testing"
 
 	"github.com/go-redis/redismock/v8"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	testBackpressure = pubsub.Backpressure{BufferSize: 16, Policy: pubsub.DropOldest}
+	testLogger       = slog.New(slog.NewTextHandler(io.Discard, nil))
 )
 
 func TestRedisPubSub(t *testing.T) {
@@ -24,9 +35,9 @@ func TestRedisPubSub(t *testing.T) {
 	t.Run("PublishComment", func(t *testing.T) {
 		t.Run("success", func(t *testing.T) {
 			client, mock := redismock.NewClientMock()
-			pubsub := NewRedisPubSub(client)
+			pubsub := NewRedisPubSub(client, testBackpressure, testLogger)
 
-			payload, err := json.Marshal(comment)
+			payload, err := encodeMessage(ctx, comment)
 			require.NoError(t, err)
 
 			channel := pubsub.getChannel(postID)
@@ -35,13 +46,14 @@ func TestRedisPubSub(t *testing.T) {
 			err = pubsub.PublishComment(ctx, postID, comment)
 			assert.NoError(t, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
+			assert.Equal(t, uint64(1), pubsub.CommentStats().Published)
 		})
 
 		t.Run("publish error", func(t *testing.T) {
 			client, mock := redismock.NewClientMock()
-			pubsub := NewRedisPubSub(client)
+			pubsub := NewRedisPubSub(client, testBackpressure, testLogger)
 
-			payload, err := json.Marshal(comment)
+			payload, err := encodeMessage(ctx, comment)
 			require.NoError(t, err)
 
 			channel := pubsub.getChannel(postID)
@@ -53,12 +65,136 @@ func TestRedisPubSub(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
+	t.Run("PublishPost", func(t *testing.T) {
+		post := &model.Post{
+			ID:    uuid.New().String(),
+			Title: "Test post",
+		}
+
+		t.Run("success", func(t *testing.T) {
+			client, mock := redismock.NewClientMock()
+			pubsub := NewRedisPubSub(client, testBackpressure, testLogger)
+
+			payload, err := encodeMessage(ctx, post)
+			require.NoError(t, err)
+
+			mock.ExpectPublish(postsChannel, payload).SetVal(1)
+
+			err = pubsub.PublishPost(ctx, post)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("publish error", func(t *testing.T) {
+			client, mock := redismock.NewClientMock()
+			pubsub := NewRedisPubSub(client, testBackpressure, testLogger)
+
+			payload, err := encodeMessage(ctx, post)
+			require.NoError(t, err)
+
+			mock.ExpectPublish(postsChannel, payload).SetErr(errors.New("publish failed"))
+
+			err = pubsub.PublishPost(ctx, post)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to publish post")
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
 	t.Run("GetChannelName", func(t *testing.T) {
 		t.Run("success", func(t *testing.T) {
-			pubsub := NewRedisPubSub(nil)
+			pubsub := NewRedisPubSub(nil, testBackpressure, testLogger)
 			postID := uuid.New()
 			expected := "comments:" + postID.String()
 			assert.Equal(t, expected, pubsub.getChannel(postID))
 		})
 	})
+
+	t.Run("SubscriptionsPerPost", func(t *testing.T) {
+		client, _ := redismock.NewClientMock()
+		pubsub := NewRedisPubSub(client, testBackpressure, testLogger)
+
+		comments := newTopic[model.Comment](pubsub.getChannel(postID), testBackpressure.Policy, &pubsub.comments, testLogger)
+		comments.add(&subscriber[model.Comment]{})
+		comments.add(&subscriber[model.Comment]{})
+		seed(pubsub, pubsub.getChannel(postID), comments)
+
+		posts := newTopic[model.Post](postsChannel, testBackpressure.Policy, &pubsub.posts, testLogger)
+		posts.add(&subscriber[model.Post]{})
+		seed(pubsub, postsChannel, posts)
+
+		assert.Equal(t, map[string]int{postID.String(): 2}, pubsub.SubscriptionsPerPost())
+	})
+
+	t.Run("DecodeMessage", func(t *testing.T) {
+		t.Run("round trip", func(t *testing.T) {
+			payload, err := encodeMessage(ctx, comment)
+			require.NoError(t, err)
+
+			var decoded model.Comment
+			_, err = decodeMessage(ctx, string(payload), &decoded)
+			require.NoError(t, err)
+			assert.Equal(t, *comment, decoded)
+		})
+
+		t.Run("trace context", func(t *testing.T) {
+			previous := otel.GetTextMapPropagator()
+			otel.SetTextMapPropagator(propagation.TraceContext{})
+			defer otel.SetTextMapPropagator(previous)
+
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{2},
+				TraceFlags: trace.FlagsSampled,
+			})
+			payload, err := encodeMessage(trace.ContextWithSpanContext(ctx, spanCtx), comment)
+			require.NoError(t, err)
+
+			var decoded model.Comment
+			msgCtx, err := decodeMessage(ctx, string(payload), &decoded)
+			require.NoError(t, err)
+			assert.Equal(t, spanCtx.TraceID(), trace.SpanContextFromContext(msgCtx).TraceID())
+			assert.True(t, trace.SpanContextFromContext(msgCtx).IsRemote())
+		})
+
+		t.Run("bare value", func(t *testing.T) {
+			payload, err := json.Marshal(comment)
+			require.NoError(t, err)
+
+			var decoded model.Comment
+			_, err = decodeMessage(ctx, string(payload), &decoded)
+			require.NoError(t, err)
+			assert.Equal(t, *comment, decoded)
+		})
+
+		t.Run("envelope", func(t *testing.T) {
+			data, err := json.Marshal(comment)
+			require.NoError(t, err)
+			payload, err := json.Marshal(map[string]any{"trace": map[string]string{}, "data": json.RawMessage(data)})
+			require.NoError(t, err)
+
+			var decoded model.Comment
+			_, err = decodeMessage(ctx, string(payload), &decoded)
+			require.NoError(t, err)
+			assert.Equal(t, *comment, decoded)
+		})
+
+		t.Run("readable without trace support", func(t *testing.T) {
+			previous := otel.GetTextMapPropagator()
+			otel.SetTextMapPropagator(propagation.TraceContext{})
+			defer otel.SetTextMapPropagator(previous)
+
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{2},
+				TraceFlags: trace.FlagsSampled,
+			})
+			payload, err := encodeMessage(trace.ContextWithSpanContext(ctx, spanCtx), comment)
+			require.NoError(t, err)
+
+			// Replicas predating the trace context decode the value directly.
+			var decoded model.Comment
+			require.NoError(t, json.Unmarshal(payload, &decoded))
+			assert.Equal(t, *comment, decoded)
+		})
+	})
 }
@@ -0,0 +1,106 @@
+package pubsub_redis
+
+import (
+	"app/internal/pubsub"
+	"app/internal/tracing"
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fanout is a topic regardless of the type of its messages. Its methods are
+// called with RedisPubSub.mu held.
+type fanout interface {
+	dispatch(payload string)
+	size() int
+	close()
+}
+
+// topic holds the local subscribers of one Redis channel.
+type topic[T any] struct {
+	channel     string
+	policy      pubsub.OverflowPolicy
+	counters    *deliveryCounters
+	logger      *slog.Logger
+	subscribers map[*subscriber[T]]struct{}
+}
+
+type subscriber[T any] struct {
+	ctx  context.Context
+	out  chan *T
+	stop func() bool
+}
+
+func newTopic[T any](channel string, policy pubsub.OverflowPolicy, counters *deliveryCounters, logger *slog.Logger) *topic[T] {
+	return &topic[T]{
+		channel:     channel,
+		policy:      policy,
+		counters:    counters,
+		logger:      logger,
+		subscribers: make(map[*subscriber[T]]struct{}),
+	}
+}
+
+func (t *topic[T]) add(sub *subscriber[T]) {
+	t.subscribers[sub] = struct{}{}
+}
+
+// remove closes the stream of sub and reports whether it was still
+// subscribed.
+func (t *topic[T]) remove(sub *subscriber[T]) bool {
+	if _, exists := t.subscribers[sub]; !exists {
+		return false
+	}
+	delete(t.subscribers, sub)
+	close(sub.out)
+	return true
+}
+
+func (t *topic[T]) size() int {
+	return len(t.subscribers)
+}
+
+func (t *topic[T]) close() {
+	for sub := range t.subscribers {
+		sub.stop()
+		t.remove(sub)
+	}
+}
+
+// dispatch decodes payload once, under a span joined to the publisher's
+// trace, and buffers a copy for every subscriber. Subscribers that fell
+// behind under the disconnect policy are removed.
+func (t *topic[T]) dispatch(payload string) {
+	var value T
+	msgCtx, err := decodeMessage(context.Background(), payload, &value)
+	_, span := startSpan(msgCtx, "receive", t.channel, trace.SpanKindConsumer)
+	span.SetAttributes(attribute.Int("messaging.subscribers", t.size()))
+	if err != nil {
+		t.logger.ErrorContext(msgCtx, "Failed to unmarshal message", "channel", t.channel, "error", err)
+		t.counters.dropped.Add(uint64(t.size()))
+		tracing.End(span, err)
+		return
+	}
+
+	var dropped int
+	for sub := range t.subscribers {
+		copied := value
+		n, ok := pubsub.Send(sub.ctx, sub.out, &copied, t.policy)
+		dropped += n
+		if !ok {
+			t.logger.WarnContext(sub.ctx, "Subscriber fell behind, disconnecting", "channel", t.channel)
+			sub.stop()
+			t.remove(sub)
+			continue
+		}
+		if t.policy != pubsub.DropNewest || n == 0 {
+			t.counters.delivered.Add(1)
+		}
+	}
+	t.counters.dropped.Add(uint64(dropped))
+
+	span.SetAttributes(attribute.Int("messaging.dropped", dropped))
+	span.End()
+}
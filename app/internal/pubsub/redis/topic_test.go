@@ -0,0 +1,153 @@
+package pubsub_redis
+
+import (
+	"app/graph/model"
+	"app/internal/pubsub"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestPubSub returns a RedisPubSub whose comment channel for postID is
+// already subscribed upstream, so that local subscribers only join its topic.
+func newTestPubSub(t *testing.T, postID uuid.UUID, backpressure pubsub.Backpressure) *RedisPubSub {
+	client, _ := redismock.NewClientMock()
+	r := NewRedisPubSub(client, backpressure, testLogger)
+	channel := r.getChannel(postID)
+	seed(r, channel, newTopic[model.Comment](channel, backpressure.Policy, &r.comments, testLogger))
+	return r
+}
+
+// seed adds f as the topic of channel, as if the connection was subscribed
+// to it already.
+func seed(r *RedisPubSub, channel string, f fanout) {
+	r.topics[channel] = &topicEntry{topic: f, state: subscribed}
+}
+
+func deliver(t *testing.T, r *RedisPubSub, channel string, comment *model.Comment) {
+	payload, err := encodeMessage(context.Background(), comment)
+	require.NoError(t, err)
+	r.deliver(channel, string(payload))
+}
+
+func TestTopicFanout(t *testing.T) {
+	postID := uuid.New()
+	r := newTestPubSub(t, postID, testBackpressure)
+	channel := r.getChannel(postID)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	first, err := r.SubscribeOnComments(ctx1, postID)
+	require.NoError(t, err)
+	second, err := r.SubscribeOnComments(ctx2, postID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{postID.String(): 2}, r.SubscriptionsPerPost())
+
+	comment := &model.Comment{ID: uuid.New().String(), Content: "Test comment"}
+	deliver(t, r, channel, comment)
+
+	received1, received2 := <-first, <-second
+	assert.Equal(t, comment, received1)
+	assert.Equal(t, comment, received2)
+	assert.NotSame(t, received1, received2)
+	assert.Equal(t, uint64(2), r.CommentStats().Delivered)
+
+	cancel1()
+	_, open := <-first
+	assert.False(t, open)
+	assert.Equal(t, map[string]int{postID.String(): 1}, r.SubscriptionsPerPost())
+
+	// The last subscriber leaving drops the topic.
+	cancel2()
+	_, open = <-second
+	assert.False(t, open)
+	assert.Empty(t, r.SubscriptionsPerPost())
+}
+
+func TestTopicDisconnectsLaggingSubscriber(t *testing.T) {
+	postID := uuid.New()
+	r := newTestPubSub(t, postID, pubsub.Backpressure{BufferSize: 1, Policy: pubsub.Disconnect})
+	channel := r.getChannel(postID)
+
+	slow, err := r.SubscribeOnComments(context.Background(), postID)
+	require.NoError(t, err)
+
+	deliver(t, r, channel, &model.Comment{ID: "1"})
+	deliver(t, r, channel, &model.Comment{ID: "2"})
+
+	received := <-slow
+	assert.Equal(t, "1", received.ID)
+	_, open := <-slow
+	assert.False(t, open)
+	assert.Empty(t, r.SubscriptionsPerPost())
+	assert.Equal(t, uint64(1), r.CommentStats().Dropped)
+}
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	postID := uuid.New()
+	r := newTestPubSub(t, postID, testBackpressure)
+
+	comments, err := r.SubscribeOnComments(context.Background(), postID)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Close())
+	_, open := <-comments
+	assert.False(t, open)
+
+	_, err = r.SubscribeOnComments(context.Background(), postID)
+	assert.ErrorIs(t, err, errClosed)
+}
+
+// BenchmarkFanout compares delivering one message to many local subscribers
+// through a shared topic with the previous design, where every subscriber
+// had its own Redis subscription and decoded every message itself.
+func BenchmarkFanout(b *testing.B) {
+	comment := &model.Comment{ID: uuid.New().String(), Content: "Test comment"}
+	payload, err := encodeMessage(context.Background(), comment)
+	require.NoError(b, err)
+
+	for _, subscribers := range []int{1, 100, 10000} {
+		b.Run(fmt.Sprintf("shared/%d", subscribers), func(b *testing.B) {
+			var counters deliveryCounters
+			t := newTopic[model.Comment]("comments:bench", pubsub.DropOldest, &counters, testLogger)
+			for range subscribers {
+				t.add(&subscriber[model.Comment]{ctx: context.Background(), out: make(chan *model.Comment, 1)})
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				t.dispatch(string(payload))
+			}
+		})
+
+		b.Run(fmt.Sprintf("per-subscriber/%d", subscribers), func(b *testing.B) {
+			outs := make([]chan *model.Comment, subscribers)
+			for i := range outs {
+				outs[i] = make(chan *model.Comment, 1)
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				for _, out := range outs {
+					var value model.Comment
+					msgCtx, err := decodeMessage(context.Background(), string(payload), &value)
+					if err != nil {
+						b.Fatal(err)
+					}
+					_, span := startSpan(msgCtx, "receive", "comments:bench", trace.SpanKindConsumer)
+					pubsub.Send(context.Background(), out, &value, pubsub.DropOldest)
+					span.End()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package pubsub_redis
+
+import (
+	"app/graph/model"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis speaks just enough of the Redis protocol for PUBLISH and a
+// pub/sub connection, and records the subscribe and unsubscribe commands it
+// receives.
+type fakeRedis struct {
+	listener net.Listener
+
+	// mu guards everything below and every write to a connection.
+	mu       sync.Mutex
+	conns    map[net.Conn]map[string]struct{}
+	commands []string
+	// subscribers are the connections that have ever subscribed.
+	subscribers map[net.Conn]struct{}
+}
+
+func newFakeRedis(t testing.TB) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	f := &fakeRedis{
+		listener:    listener,
+		conns:       make(map[net.Conn]map[string]struct{}),
+		subscribers: make(map[net.Conn]struct{}),
+	}
+	go f.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		f.drop()
+	})
+	return f
+}
+
+func (f *fakeRedis) client() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: f.listener.Addr().String(), PoolSize: 1})
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.conns[conn] = make(map[string]struct{})
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer func() {
+		f.mu.Lock()
+		delete(f.conns, conn)
+		f.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		f.exec(conn, args)
+	}
+}
+
+func (f *fakeRedis) exec(conn net.Conn, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	channels := f.conns[conn]
+	var reply strings.Builder
+	switch command := strings.ToLower(args[0]); command {
+	case "subscribe", "unsubscribe":
+		f.subscribers[conn] = struct{}{}
+		for _, channel := range args[1:] {
+			f.commands = append(f.commands, command+" "+channel)
+			if command == "subscribe" {
+				channels[channel] = struct{}{}
+			} else {
+				delete(channels, channel)
+			}
+			fmt.Fprintf(&reply, "*3\r\n%s%s:%d\r\n", bulk(command), bulk(channel), len(channels))
+		}
+	case "ping":
+		if len(channels) > 0 {
+			reply.WriteString("*2\r\n" + bulk("pong") + bulk(""))
+		} else {
+			reply.WriteString("+PONG\r\n")
+		}
+	case "publish":
+		fmt.Fprintf(&reply, ":%d\r\n", f.publish(args[1], args[2]))
+	default:
+		fmt.Fprintf(&reply, "-ERR unknown command '%s'\r\n", args[0])
+	}
+	conn.Write([]byte(reply.String()))
+}
+
+// publish sends payload to every connection subscribed to channel. f.mu
+// must be held.
+func (f *fakeRedis) publish(channel string, payload string) int {
+	var receivers int
+	for conn, channels := range f.conns {
+		if _, exists := channels[channel]; exists {
+			conn.Write([]byte("*3\r\n" + bulk("message") + bulk(channel) + bulk(payload)))
+			receivers++
+		}
+	}
+	return receivers
+}
+
+// drop closes every open connection.
+func (f *fakeRedis) drop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for conn := range f.conns {
+		conn.Close()
+	}
+}
+
+func (f *fakeRedis) subscribed(channel string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, channels := range f.conns {
+		if _, exists := channels[channel]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeRedis) received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.commands...)
+}
+
+func (f *fakeRedis) subscriberConns() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.subscribers)
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, errors.New("malformed command")
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, errors.New("malformed argument")
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, arg); err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func receive(t *testing.T, comments <-chan *model.Comment) *model.Comment {
+	t.Helper()
+	select {
+	case comment := <-comments:
+		return comment
+	case <-time.After(time.Second):
+		t.Fatal("no comment received")
+		return nil
+	}
+}
+
+func TestUpstreamSubscription(t *testing.T) {
+	ctx := context.Background()
+	comment := &model.Comment{ID: uuid.New().String(), Content: "Test comment"}
+
+	t.Run("shared by subscribers of a channel", func(t *testing.T) {
+		server := newFakeRedis(t)
+		r := NewRedisPubSub(server.client(), testBackpressure, testLogger)
+		defer r.Close()
+		postID := uuid.New()
+		channel := r.getChannel(postID)
+
+		ctx1, cancel1 := context.WithCancel(ctx)
+		defer cancel1()
+		ctx2, cancel2 := context.WithCancel(ctx)
+		defer cancel2()
+
+		first, err := r.SubscribeOnComments(ctx1, postID)
+		require.NoError(t, err)
+		second, err := r.SubscribeOnComments(ctx2, postID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"subscribe " + channel}, server.received())
+
+		require.NoError(t, r.PublishComment(ctx, postID, comment))
+		assert.Equal(t, comment, receive(t, first))
+		assert.Equal(t, comment, receive(t, second))
+
+		cancel1()
+		cancel2()
+		assert.Eventually(t, func() bool { return !server.subscribed(channel) }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"subscribe " + channel, "unsubscribe " + channel}, server.received())
+
+		third, err := r.SubscribeOnComments(ctx, postID)
+		require.NoError(t, err)
+		require.NoError(t, r.PublishComment(ctx, postID, comment))
+		assert.Equal(t, comment, receive(t, third))
+
+		assert.Equal(t, []string{"subscribe " + channel, "unsubscribe " + channel, "subscribe " + channel}, server.received())
+		assert.Equal(t, 1, server.subscriberConns())
+	})
+
+	t.Run("subscribers of different channels share the connection", func(t *testing.T) {
+		server := newFakeRedis(t)
+		r := NewRedisPubSub(server.client(), testBackpressure, testLogger)
+		defer r.Close()
+
+		_, err := r.SubscribeOnComments(ctx, uuid.New())
+		require.NoError(t, err)
+		_, err = r.SubscribeOnPosts(ctx)
+		require.NoError(t, err)
+
+		assert.Len(t, server.received(), 2)
+		assert.Equal(t, 1, server.subscriberConns())
+	})
+
+	t.Run("resubscribes after reconnecting", func(t *testing.T) {
+		server := newFakeRedis(t)
+		r := NewRedisPubSub(server.client(), testBackpressure, testLogger)
+		defer r.Close()
+		postID := uuid.New()
+		channel := r.getChannel(postID)
+
+		comments, err := r.SubscribeOnComments(ctx, postID)
+		require.NoError(t, err)
+
+		server.drop()
+		assert.Eventually(t, func() bool { return server.subscriberConns() == 2 && server.subscribed(channel) }, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, r.PublishComment(ctx, postID, comment))
+		assert.Equal(t, comment, receive(t, comments))
+	})
+
+	t.Run("failing to subscribe", func(t *testing.T) {
+		server := newFakeRedis(t)
+		client := server.client()
+		server.listener.Close()
+		r := NewRedisPubSub(client, testBackpressure, testLogger)
+		defer r.Close()
+
+		_, err := r.SubscribeOnComments(ctx, uuid.New())
+		assert.Error(t, err)
+		assert.Empty(t, r.SubscriptionsPerPost())
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		assert.Empty(t, r.topics)
+	})
+}
+
+// BenchmarkConnections compares delivering one message to many subscribers
+// of a channel through the shared connection with giving every subscriber
+// its own Redis subscription, and reports the connections and goroutines,
+// those of the fake server included, each holds.
+func BenchmarkConnections(b *testing.B) {
+	comment := &model.Comment{ID: uuid.New().String(), Content: "Test comment"}
+	payload, err := encodeMessage(context.Background(), comment)
+	require.NoError(b, err)
+	postID := uuid.New()
+
+	for _, subscribers := range []int{1, 100, 1000} {
+		b.Run(fmt.Sprintf("shared/%d", subscribers), func(b *testing.B) {
+			server := newFakeRedis(b)
+			r := NewRedisPubSub(server.client(), testBackpressure, testLogger)
+			defer r.Close()
+			channel := r.getChannel(postID)
+
+			goroutines := runtime.NumGoroutine()
+			outs := make([]<-chan *model.Comment, subscribers)
+			for i := range outs {
+				outs[i], err = r.SubscribeOnComments(context.Background(), postID)
+				require.NoError(b, err)
+			}
+			goroutines = runtime.NumGoroutine() - goroutines
+
+			for b.Loop() {
+				server.mu.Lock()
+				server.publish(channel, string(payload))
+				server.mu.Unlock()
+				for _, out := range outs {
+					<-out
+				}
+			}
+			b.ReportMetric(float64(server.subscriberConns()), "conns")
+			b.ReportMetric(float64(goroutines), "goroutines")
+		})
+
+		b.Run(fmt.Sprintf("per-subscriber/%d", subscribers), func(b *testing.B) {
+			server := newFakeRedis(b)
+			client := server.client()
+			defer client.Close()
+			channel := commentsChannelPrefix + postID.String()
+
+			goroutines := runtime.NumGoroutine()
+			outs := make([]<-chan *redis.Message, subscribers)
+			for i := range outs {
+				sub := client.Subscribe(context.Background(), channel)
+				defer sub.Close()
+				_, err := sub.Receive(context.Background())
+				require.NoError(b, err)
+				outs[i] = sub.Channel()
+			}
+			goroutines = runtime.NumGoroutine() - goroutines
+
+			for b.Loop() {
+				server.mu.Lock()
+				server.publish(channel, string(payload))
+				server.mu.Unlock()
+				for _, out := range outs {
+					var value model.Comment
+					if _, err := decodeMessage(context.Background(), (<-out).Payload, &value); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(server.subscriberConns()), "conns")
+			b.ReportMetric(float64(goroutines), "goroutines")
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// UserIDHeader carries the ID of the acting user. There is no authentication
+// yet, so the ID is only passed along and never used as a limiting key:
+// anyone could send someone else's ID and use up their budget. See the TODO
+// in Limiter.take for keying by user.
+const UserIDHeader = "X-User-ID"
+
+// Client identifies whoever sent a request.
+type Client struct {
+	IP     string
+	UserID string
+}
+
+type clientKey struct{}
+
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
+
+// ClientMiddleware records the client of every request. Behind
+// trustedProxies proxies, each appending the address it was connected from
+// to X-Forwarded-For, the IP is the entry appended by the outermost one;
+// entries further left come from the client and may be forged.
+func ClientMiddleware(next http.Handler, trustedProxies int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := Client{IP: clientIP(r, trustedProxies), UserID: r.Header.Get(UserIDHeader)}
+		next.ServeHTTP(w, r.WithContext(WithClient(r.Context(), client)))
+	})
+}
+
+func clientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var forwarded []string
+		for _, value := range r.Header.Values("X-Forwarded-For") {
+			for _, entry := range strings.Split(value, ",") {
+				forwarded = append(forwarded, strings.TrimSpace(entry))
+			}
+		}
+		// Fewer entries mean the request did not come through all the
+		// proxies, so the connection itself is the client.
+		if len(forwarded) >= trustedProxies {
+			return forwarded[len(forwarded)-trustedProxies]
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// connection counts the subscriptions running on one websocket.
+type connection struct {
+	subscriptions atomic.Int64
+}
+
+type connectionKey struct{}
+
+// WebsocketInit prepares a websocket connection for limiting: browsers cannot
+// set headers on the upgrade request, so the user ID may also come as userId
+// in the init payload.
+func WebsocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	client, _ := ClientFromContext(ctx)
+	if userId := payload.GetString("userId"); userId != "" {
+		client.UserID = userId
+	}
+	ctx = WithClient(ctx, client)
+	return context.WithValue(ctx, connectionKey{}, &connection{}), nil, nil
+}
+
+func connectionFromContext(ctx context.Context) *connection {
+	conn, _ := ctx.Value(connectionKey{}).(*connection)
+	return conn
+}
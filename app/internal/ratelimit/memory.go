@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore keeps buckets in the process. Buckets of the least recently
+// seen keys are evicted past size; an evicted bucket starts full again.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets *lru.Cache[string, *bucket]
+	now     func() time.Time
+}
+
+func NewMemoryStore(size int) (*MemoryStore, error) {
+	buckets, err := lru.New[string, *bucket](size)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStore{buckets: buckets, now: time.Now}, nil
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	burst := float64(limit.Requests)
+	interval := limit.interval()
+
+	b, ok := s.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		s.buckets.Add(key, b)
+	}
+
+	b.tokens = min(burst, b.tokens+float64(now.Sub(b.last))/float64(interval))
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	return false, time.Duration((1 - b.tokens) * float64(interval)), nil
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRateLimited          = errors.New("Rate limit exceeded")
+	ErrTooManySubscriptions = errors.New("Too many subscriptions on this connection")
+)
+
+// Limit is a token bucket holding up to Requests tokens that refills
+// completely over Per.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit reads a limit written as "10/1m".
+func ParseLimit(s string) (Limit, error) {
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected requests/duration", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(per))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: duration must be positive", s)
+	}
+
+	return Limit{Requests: n, Per: d}, nil
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+// Store keeps token buckets. Take removes a token from the bucket under key
+// and, when the bucket is empty, reports how long until the next one.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// Error is returned to clients that ran out of tokens.
+type Error struct {
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s, retry in %ds", ErrRateLimited, e.retryAfterSeconds())
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Extensions tells clients how many seconds to wait before retrying.
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"retryAfter": e.retryAfterSeconds()}
+}
+
+func (e *Error) retryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
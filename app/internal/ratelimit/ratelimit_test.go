@@ -0,0 +1,274 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		input string
+		limit Limit
+		err   bool
+	}{
+		{"10/1m", Limit{Requests: 10, Per: time.Minute}, false},
+		{" 5 / 30s ", Limit{Requests: 5, Per: 30 * time.Second}, false},
+		{"10", Limit{}, true},
+		{"0/1m", Limit{}, true},
+		{"10/soon", Limit{}, true},
+		{"10/-1s", Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			limit, err := ParseLimit(tt.input)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Per: time.Minute}
+
+	store, err := NewMemoryStore(10)
+	require.NoError(t, err)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	t.Run("burst then wait", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			allowed, _, err := store.Take(ctx, "a", limit)
+			require.NoError(t, err)
+			assert.True(t, allowed)
+		}
+
+		allowed, wait, err := store.Take(ctx, "a", limit)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 30*time.Second, wait)
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		allowed, _, err := store.Take(ctx, "b", limit)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("refills over time", func(t *testing.T) {
+		now = now.Add(20 * time.Second)
+		allowed, wait, err := store.Take(ctx, "a", limit)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 10*time.Second, wait)
+
+		now = now.Add(10 * time.Second)
+		allowed, _, err = store.Take(ctx, "a", limit)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 10, Per: time.Minute}
+	now := time.UnixMilli(1700000000000)
+
+	t.Run("denied", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		store := NewRedisStore(client)
+		store.now = func() time.Time { return now }
+
+		mock.ExpectEvalSha(takeScript.Hash(), []string{"ratelimit:user:1:createComment"}, 10, int64(6000), now.UnixMilli()).
+			SetVal([]interface{}{int64(0), int64(1500)})
+
+		allowed, wait, err := store.Take(ctx, "user:1:createComment", limit)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 1500*time.Millisecond, wait)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		store := NewRedisStore(client)
+		store.now = func() time.Time { return now }
+
+		mock.ExpectEvalSha(takeScript.Hash(), []string{"ratelimit:k"}, 10, int64(6000), now.UnixMilli()).
+			SetErr(errors.New("connection refused"))
+
+		_, _, err := store.Take(ctx, "k", limit)
+		assert.ErrorContains(t, err, "failed to take rate limit token")
+	})
+}
+
+type stubStore struct {
+	denied map[string]time.Duration
+	keys   []string
+	err    error
+}
+
+func (s *stubStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	s.keys = append(s.keys, key)
+	if wait, ok := s.denied[key]; ok {
+		return false, wait, s.err
+	}
+	return true, 0, s.err
+}
+
+func fieldContext(ctx context.Context, object, field string) context.Context {
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: object,
+		Field:  graphql.CollectedField{Field: &ast.Field{Name: field}},
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	resolved := func(ctx context.Context) (any, error) { return "ok", nil }
+	client := Client{IP: "10.0.0.1", UserID: "u1"}
+
+	t.Run("takes from ip bucket", func(t *testing.T) {
+		store := &stubStore{}
+		limiter := &Limiter{Store: store, Logger: logger}
+
+		ctx := fieldContext(WithClient(context.Background(), client), "Mutation", "createComment")
+		res, err := limiter.InterceptField(ctx, resolved)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", res)
+		assert.Equal(t, []string{"ip:10.0.0.1:createComment"}, store.keys)
+	})
+
+	t.Run("rejects with wait", func(t *testing.T) {
+		store := &stubStore{denied: map[string]time.Duration{
+			"ip:10.0.0.1:createComment": 5 * time.Second,
+		}}
+		limiter := &Limiter{Store: store, Logger: logger}
+
+		ctx := fieldContext(WithClient(context.Background(), client), "Mutation", "createComment")
+		_, err := limiter.InterceptField(ctx, resolved)
+
+		var rateErr *Error
+		require.ErrorAs(t, err, &rateErr)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, map[string]interface{}{"retryAfter": 5}, rateErr.Extensions())
+	})
+
+	t.Run("forged user id does not lock out the user", func(t *testing.T) {
+		store, err := NewMemoryStore(10)
+		require.NoError(t, err)
+		limiter := &Limiter{Store: store, Mutation: Limit{Requests: 1, Per: time.Minute}, Logger: logger}
+
+		forger := WithClient(context.Background(), Client{IP: "10.0.0.2", UserID: "u1"})
+		for range 2 {
+			_, _ = limiter.InterceptField(fieldContext(forger, "Mutation", "createComment"), resolved)
+		}
+
+		_, err = limiter.InterceptField(fieldContext(WithClient(context.Background(), client), "Mutation", "createComment"), resolved)
+		assert.NoError(t, err)
+	})
+
+	t.Run("queries are not limited", func(t *testing.T) {
+		store := &stubStore{}
+		limiter := &Limiter{Store: store, Logger: logger}
+
+		_, err := limiter.InterceptField(fieldContext(WithClient(context.Background(), client), "Query", "posts"), resolved)
+		require.NoError(t, err)
+		assert.Empty(t, store.keys)
+	})
+
+	t.Run("store failure lets request through", func(t *testing.T) {
+		store := &stubStore{err: errors.New("connection refused")}
+		limiter := &Limiter{Store: store, Logger: logger}
+
+		_, err := limiter.InterceptField(fieldContext(WithClient(context.Background(), client), "Mutation", "createPost"), resolved)
+		assert.NoError(t, err)
+	})
+
+	t.Run("caps subscriptions per connection", func(t *testing.T) {
+		limiter := &Limiter{Store: &stubStore{}, MaxSubscriptionsPerConnection: 1, Logger: logger}
+		connCtx, _, err := WebsocketInit(WithClient(context.Background(), client), nil)
+		require.NoError(t, err)
+
+		first, cancel := context.WithCancel(connCtx)
+		_, err = limiter.InterceptField(fieldContext(first, "Subscription", "commentAdded"), resolved)
+		require.NoError(t, err)
+
+		_, err = limiter.InterceptField(fieldContext(connCtx, "Subscription", "commentAdded"), resolved)
+		assert.ErrorIs(t, err, ErrTooManySubscriptions)
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			_, err := limiter.InterceptField(fieldContext(connCtx, "Subscription", "postPublished"), resolved)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("limit lookup", func(t *testing.T) {
+		limiter := &Limiter{
+			Mutation:     Limit{Requests: 60, Per: time.Minute},
+			Subscription: Limit{Requests: 30, Per: time.Minute},
+			Fields:       map[string]Limit{"createComment": {Requests: 10, Per: time.Minute}},
+		}
+		assert.Equal(t, 10, limiter.limitFor("Mutation", "createComment").Requests)
+		assert.Equal(t, 60, limiter.limitFor("Mutation", "editPost").Requests)
+		assert.Equal(t, 30, limiter.limitFor("Subscription", "commentAdded").Requests)
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwarded      []string
+		trustedProxies int
+		expected       string
+	}{
+		{"direct", nil, 0, "192.0.2.1"},
+		{"forwarded header ignored without proxies", []string{"203.0.113.7"}, 0, "192.0.2.1"},
+		{"one proxy", []string{"203.0.113.7"}, 1, "203.0.113.7"},
+		{"forged entry before proxy", []string{"1.2.3.4, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"two proxies", []string{"1.2.3.4, 203.0.113.7", "10.0.0.5"}, 2, "203.0.113.7"},
+		{"fewer entries than proxies", []string{"203.0.113.7"}, 2, "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/query", nil)
+			r.RemoteAddr = "192.0.2.1:54321"
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			assert.Equal(t, tt.expected, clientIP(r, tt.trustedProxies))
+		})
+	}
+}
+
+func TestWebsocketInit(t *testing.T) {
+	ctx := WithClient(context.Background(), Client{IP: "10.0.0.1"})
+
+	ctx, _, err := WebsocketInit(ctx, map[string]any{"userId": "u1"})
+	require.NoError(t, err)
+
+	client, ok := ClientFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, Client{IP: "10.0.0.1", UserID: "u1"}, client)
+	assert.NotNil(t, connectionFromContext(ctx))
+}
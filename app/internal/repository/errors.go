@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrContextCanceled = errors.New("Context canceled")
 	ErrNotFound        = errors.New("No records found")
+
+	ErrParentOnDifferentPost = errors.New("Parent comment belongs to another post")
 )
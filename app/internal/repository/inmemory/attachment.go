@@ -0,0 +1,60 @@
+package inmemory
+
+import (
+	"app/internal/entity"
+	"app/internal/repository"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type attachmentOwnerKey struct {
+	ownerType entity.AttachmentOwner
+	ownerId   uuid.UUID
+}
+
+type AttachmentRepo struct {
+	attachments map[uuid.UUID]entity.Attachment
+	ownerIndex  map[attachmentOwnerKey][]uuid.UUID
+
+	mu sync.RWMutex
+}
+
+func NewAttachmentRepo(initSize int) *AttachmentRepo {
+	return &AttachmentRepo{
+		attachments: make(map[uuid.UUID]entity.Attachment, initSize),
+		ownerIndex:  make(map[attachmentOwnerKey][]uuid.UUID, initSize),
+	}
+}
+
+func (r *AttachmentRepo) Create(ctx context.Context, attachment *entity.Attachment) error {
+	if err := ctx.Err(); err != nil {
+		return repository.ErrContextCanceled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attachments[attachment.Id] = *attachment
+
+	key := attachmentOwnerKey{ownerType: attachment.OwnerType, ownerId: attachment.OwnerId}
+	r.ownerIndex[key] = append(r.ownerIndex[key], attachment.Id)
+
+	return nil
+}
+
+func (r *AttachmentRepo) GetByOwner(ctx context.Context, ownerType entity.AttachmentOwner, ownerId uuid.UUID) ([]entity.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := r.ownerIndex[attachmentOwnerKey{ownerType: ownerType, ownerId: ownerId}]
+	result := make([]entity.Attachment, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, r.attachments[id])
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package inmemory_test
+
+import (
+	"app/internal/entity"
+	"app/internal/repository"
+	"app/internal/repository/inmemory"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryAttachmentRepo(t *testing.T) {
+	ctx := context.Background()
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	postID := uuid.New()
+	newAttachment := func(ownerType entity.AttachmentOwner, ownerId uuid.UUID) entity.Attachment {
+		return entity.Attachment{
+			Id:          uuid.New(),
+			OwnerType:   ownerType,
+			OwnerId:     ownerId,
+			UserId:      uuid.New(),
+			Filename:    "photo.png",
+			ContentType: "image/png",
+			Size:        128,
+			BlobKey:     "attachments/photo.png",
+			CreatedAt:   time.Now(),
+		}
+	}
+
+	t.Run("GetByOwner returns attachments in upload order", func(t *testing.T) {
+		repo := inmemory.NewAttachmentRepo(10)
+		first := newAttachment(entity.AttachmentOwnerPost, postID)
+		second := newAttachment(entity.AttachmentOwnerPost, postID)
+		other := newAttachment(entity.AttachmentOwnerComment, postID)
+
+		assert.NoError(t, repo.Create(ctx, &first))
+		assert.NoError(t, repo.Create(ctx, &second))
+		assert.NoError(t, repo.Create(ctx, &other))
+
+		result, err := repo.GetByOwner(ctx, entity.AttachmentOwnerPost, postID)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Attachment{first, second}, result)
+	})
+
+	t.Run("GetByOwner without attachments", func(t *testing.T) {
+		repo := inmemory.NewAttachmentRepo(10)
+
+		result, err := repo.GetByOwner(ctx, entity.AttachmentOwnerPost, postID)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		repo := inmemory.NewAttachmentRepo(10)
+		attachment := newAttachment(entity.AttachmentOwnerPost, postID)
+
+		assert.ErrorIs(t, repo.Create(canceledCtx, &attachment), repository.ErrContextCanceled)
+
+		_, err := repo.GetByOwner(canceledCtx, entity.AttachmentOwnerPost, postID)
+		assert.ErrorIs(t, err, repository.ErrContextCanceled)
+	})
+}
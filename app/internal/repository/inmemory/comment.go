@@ -3,8 +3,11 @@ package inmemory
 import (
 	"app/internal/entity"
 	"app/internal/repository"
+	"bytes"
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,6 +16,8 @@ type CommentRepo struct {
 	comments     map[uuid.UUID]entity.Comment
 	postIndex    map[uuid.UUID][]uuid.UUID
 	repliesIndex map[uuid.UUID][]uuid.UUID
+	userIndex    map[uuid.UUID][]uuid.UUID
+	history      map[uuid.UUID][]entity.CommentEdit
 
 	mu sync.RWMutex
 }
@@ -22,6 +27,8 @@ func NewCommentRepo(initSize int) *CommentRepo {
 		comments:     make(map[uuid.UUID]entity.Comment, initSize),
 		postIndex:    make(map[uuid.UUID][]uuid.UUID, initSize),
 		repliesIndex: make(map[uuid.UUID][]uuid.UUID, initSize),
+		userIndex:    make(map[uuid.UUID][]uuid.UUID, initSize),
+		history:      make(map[uuid.UUID][]entity.CommentEdit, initSize),
 	}
 }
 
@@ -32,9 +39,20 @@ func (r *CommentRepo) Create(ctx context.Context, comment *entity.Comment) error
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if comment.ParentId != nil {
+		parent, exists := r.comments[*comment.ParentId]
+		if !exists {
+			return repository.ErrNotFound
+		}
+		if parent.PostId != comment.PostId {
+			return repository.ErrParentOnDifferentPost
+		}
+	}
+
 	r.comments[comment.Id] = *comment
 
 	r.postIndex[comment.PostId] = append(r.postIndex[comment.PostId], comment.Id)
+	r.userIndex[comment.UserId] = append(r.userIndex[comment.UserId], comment.Id)
 
 	if comment.ParentId != nil {
 		r.repliesIndex[*comment.ParentId] = append(r.repliesIndex[*comment.ParentId], comment.Id)
@@ -58,6 +76,137 @@ func (r *CommentRepo) GetOneById(ctx context.Context, commentId uuid.UUID) (*ent
 	return &comment, nil
 }
 
+func (r *CommentRepo) Update(ctx context.Context, comment *entity.Comment) error {
+	if err := ctx.Err(); err != nil {
+		return repository.ErrContextCanceled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, exists := r.comments[comment.Id]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	if previous.Content != comment.Content {
+		editedAt := time.Now()
+		if comment.EditedAt != nil {
+			editedAt = *comment.EditedAt
+		}
+		r.history[comment.Id] = append(r.history[comment.Id], entity.CommentEdit{
+			Id:        uuid.New(),
+			CommentId: comment.Id,
+			Content:   previous.Content,
+			CreatedAt: editedAt,
+		})
+	}
+
+	// Only the content can change, the thread position stays where it was.
+	previous.Content = comment.Content
+	previous.EditedAt = comment.EditedAt
+	r.comments[comment.Id] = previous
+
+	return nil
+}
+
+func (r *CommentRepo) GetHistory(ctx context.Context, commentId uuid.UUID) ([]entity.CommentEdit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.history[commentId]
+	result := make([]entity.CommentEdit, len(history))
+	copy(result, history)
+
+	return result, nil
+}
+
+func (r *CommentRepo) SetLocked(ctx context.Context, commentId uuid.UUID, locked bool) error {
+	if err := ctx.Err(); err != nil {
+		return repository.ErrContextCanceled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment, exists := r.comments[commentId]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	comment.Locked = locked
+	r.comments[commentId] = comment
+	return nil
+}
+
+func (r *CommentRepo) IsThreadLocked(ctx context.Context, commentId uuid.UUID) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, exists := r.comments[commentId]
+	if !exists {
+		return false, repository.ErrNotFound
+	}
+	if comment.Locked {
+		return true, nil
+	}
+
+	for _, id := range comment.Path {
+		if r.comments[id].Locked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *CommentRepo) GetAncestors(ctx context.Context, commentId uuid.UUID) ([]entity.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, exists := r.comments[commentId]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	ancestors := make([]entity.Comment, 0, len(comment.Path))
+	for _, id := range comment.Path {
+		if ancestor, exists := r.comments[id]; exists {
+			ancestors = append(ancestors, ancestor)
+		}
+	}
+	return ancestors, nil
+}
+
+func (r *CommentRepo) GetCrossPostReplies(ctx context.Context) ([]entity.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	replies := make([]entity.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.ParentId == nil {
+			continue
+		}
+		if parent, exists := r.comments[*comment.ParentId]; exists && parent.PostId != comment.PostId {
+			replies = append(replies, comment)
+		}
+	}
+
+	sort.Slice(replies, func(i, j int) bool {
+		return replies[i].CreatedAt.Before(replies[j].CreatedAt)
+	})
+	return replies, nil
+}
+
 func (r *CommentRepo) GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]entity.Comment, error) {
 	if err := ctx.Err(); err != nil {
 		return []entity.Comment{}, repository.ErrContextCanceled
@@ -118,3 +267,60 @@ func (r *CommentRepo) GetCommentReplies(ctx context.Context, parentId uuid.UUID,
 
 	return result, nil
 }
+
+func (r *CommentRepo) GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID) ([]entity.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return []entity.Comment{}, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if limit <= 0 {
+		return []entity.Comment{}, nil
+	}
+
+	commentIds := r.userIndex[userId]
+	userComments := make([]entity.Comment, 0, len(commentIds))
+	for _, id := range commentIds {
+		userComments = append(userComments, r.comments[id])
+	}
+
+	// Newest first, ties broken by id like ORDER BY created_at DESC, id DESC
+	sort.Slice(userComments, func(i, j int) bool {
+		if !userComments[i].CreatedAt.Equal(userComments[j].CreatedAt) {
+			return userComments[i].CreatedAt.After(userComments[j].CreatedAt)
+		}
+		return bytes.Compare(userComments[i].Id[:], userComments[j].Id[:]) > 0
+	})
+
+	start := 0
+	if after != nil {
+		start = len(userComments)
+		for ind, comment := range userComments {
+			if comment.Id == *after {
+				start = ind + 1
+				break
+			}
+		}
+	}
+
+	end := start + limit
+	if end > len(userComments) {
+		end = len(userComments)
+	}
+
+	result := make([]entity.Comment, 0, end-start)
+	result = append(result, userComments[start:end]...)
+
+	return result, nil
+}
+
+func (r *CommentRepo) CountByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.userIndex[userId]), nil
+}
@@ -34,6 +34,8 @@ func TestCommentRepo(t *testing.T) {
 		ParentId:  &parentCommentID,
 		Content:   "Reply comment",
 		CreatedAt: time.Now(),
+		Path:      []uuid.UUID{parentCommentID},
+		Depth:     1,
 	}
 
 	tests := []struct {
@@ -56,6 +58,29 @@ func TestCommentRepo(t *testing.T) {
 				assert.ErrorIs(t, err, repository.ErrContextCanceled)
 			},
 		},
+		{
+			name: "Create/parent not found",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				err := repo.Create(context.Background(), &replyComment)
+				assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
+		{
+			name: "Create/parent on different post",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				parentComment := baseComment
+				parentComment.Id = parentCommentID
+				parentComment.PostId = uuid.New()
+				_ = repo.Create(context.Background(), &parentComment)
+
+				err := repo.Create(context.Background(), &replyComment)
+				assert.ErrorIs(t, err, repository.ErrParentOnDifferentPost)
+
+				replies, err := repo.GetCrossPostReplies(context.Background())
+				assert.NoError(t, err)
+				assert.Empty(t, replies)
+			},
+		},
 		{
 			name: "GetOneByID/success",
 			run: func(t *testing.T, repo *inmemory.CommentRepo) {
@@ -134,6 +159,189 @@ func TestCommentRepo(t *testing.T) {
 				assert.Len(t, result, 2)
 			},
 		},
+		{
+			name: "GetByUser/newest first",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				ids := make([]uuid.UUID, 0, 3)
+				for i := 0; i < 3; i++ {
+					comment := baseComment
+					comment.Id = uuid.New()
+					comment.CreatedAt = baseComment.CreatedAt.Add(time.Duration(i) * time.Second)
+					_ = repo.Create(context.Background(), &comment)
+					ids = append(ids, comment.Id)
+				}
+
+				result, err := repo.GetByUser(context.Background(), userID, 2, nil)
+				assert.NoError(t, err)
+				assert.Len(t, result, 2)
+				assert.Equal(t, ids[2], result[0].Id)
+				assert.Equal(t, ids[1], result[1].Id)
+
+				result, err = repo.GetByUser(context.Background(), userID, 2, &ids[1])
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+				assert.Equal(t, ids[0], result[0].Id)
+			},
+		},
+		{
+			name: "GetByUser/same created_at ordered by id",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				low := baseComment
+				low.Id = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+				high := baseComment
+				high.Id = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+				_ = repo.Create(context.Background(), &low)
+				_ = repo.Create(context.Background(), &high)
+
+				result, err := repo.GetByUser(context.Background(), userID, 1, nil)
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+				assert.Equal(t, high.Id, result[0].Id)
+
+				result, err = repo.GetByUser(context.Background(), userID, 1, &high.Id)
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+				assert.Equal(t, low.Id, result[0].Id)
+			},
+		},
+		{
+			name: "GetByUser/unknown cursor",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				_ = repo.Create(context.Background(), &baseComment)
+
+				result, err := repo.GetByUser(context.Background(), userID, 10, &nonExistentID)
+				assert.NoError(t, err)
+				assert.Empty(t, result)
+			},
+		},
+		{
+			name: "CountByUser/success",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				parentComment := baseComment
+				parentComment.Id = parentCommentID
+				_ = repo.Create(context.Background(), &parentComment)
+				_ = repo.Create(context.Background(), &replyComment)
+
+				count, err := repo.CountByUser(context.Background(), userID)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, count)
+
+				count, err = repo.CountByUser(context.Background(), nonExistentID)
+				assert.NoError(t, err)
+				assert.Zero(t, count)
+			},
+		},
+		{
+			name: "Update/keeps history",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				_ = repo.Create(context.Background(), &baseComment)
+
+				for _, content := range []string{"first edit", "second edit"} {
+					edited := baseComment
+					editedAt := time.Now()
+					_, err := edited.Edit(content, editedAt)
+					assert.NoError(t, err)
+					assert.NoError(t, repo.Update(context.Background(), &edited))
+				}
+
+				result, err := repo.GetOneById(context.Background(), baseComment.Id)
+				assert.NoError(t, err)
+				assert.Equal(t, "second edit", result.Content)
+				assert.True(t, result.IsEdited())
+
+				history, err := repo.GetHistory(context.Background(), baseComment.Id)
+				assert.NoError(t, err)
+				assert.Len(t, history, 2)
+				assert.Equal(t, baseComment.Content, history[0].Content)
+				assert.Equal(t, "first edit", history[1].Content)
+			},
+		},
+		{
+			name: "Update/same content",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				_ = repo.Create(context.Background(), &baseComment)
+				assert.NoError(t, repo.Update(context.Background(), &baseComment))
+
+				history, err := repo.GetHistory(context.Background(), baseComment.Id)
+				assert.NoError(t, err)
+				assert.Empty(t, history)
+			},
+		},
+		{
+			name: "Update/not found",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				err := repo.Update(context.Background(), &baseComment)
+				assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
+		{
+			name: "GetHistory/canceled context",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_, err := repo.GetHistory(ctx, baseComment.Id)
+				assert.ErrorIs(t, err, repository.ErrContextCanceled)
+			},
+		},
+		{
+			name: "IsThreadLocked/locked ancestor",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				parentComment := baseComment
+				parentComment.Id = parentCommentID
+				_ = repo.Create(context.Background(), &parentComment)
+				_ = repo.Create(context.Background(), &replyComment)
+
+				locked, err := repo.IsThreadLocked(context.Background(), replyComment.Id)
+				assert.NoError(t, err)
+				assert.False(t, locked)
+
+				assert.NoError(t, repo.SetLocked(context.Background(), parentCommentID, true))
+
+				locked, err = repo.IsThreadLocked(context.Background(), replyComment.Id)
+				assert.NoError(t, err)
+				assert.True(t, locked)
+
+				result, err := repo.GetOneById(context.Background(), parentCommentID)
+				assert.NoError(t, err)
+				assert.True(t, result.Locked)
+
+				assert.NoError(t, repo.SetLocked(context.Background(), parentCommentID, false))
+
+				locked, err = repo.IsThreadLocked(context.Background(), replyComment.Id)
+				assert.NoError(t, err)
+				assert.False(t, locked)
+			},
+		},
+		{
+			name: "GetAncestors",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				parentComment := baseComment
+				parentComment.Id = parentCommentID
+				_ = repo.Create(context.Background(), &parentComment)
+				_ = repo.Create(context.Background(), &replyComment)
+
+				ancestors, err := repo.GetAncestors(context.Background(), replyComment.Id)
+				assert.NoError(t, err)
+				assert.Equal(t, []entity.Comment{parentComment}, ancestors)
+
+				ancestors, err = repo.GetAncestors(context.Background(), parentCommentID)
+				assert.NoError(t, err)
+				assert.Empty(t, ancestors)
+
+				_, err = repo.GetAncestors(context.Background(), nonExistentID)
+				assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
+		{
+			name: "IsThreadLocked/not found",
+			run: func(t *testing.T, repo *inmemory.CommentRepo) {
+				_, err := repo.IsThreadLocked(context.Background(), nonExistentID)
+				assert.ErrorIs(t, err, repository.ErrNotFound)
+
+				err = repo.SetLocked(context.Background(), nonExistentID, true)
+				assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -6,8 +6,9 @@ import (
 
 func NewRepoHolder(initSize int) *repository.RepoHolder {
 	return &repository.RepoHolder{
-		UserRepo:    NewUserRepo(initSize),
-		PostRepo:    NewPostRepo(initSize),
-		CommentRepo: NewCommentRepo(initSize),
+		UserRepo:       NewUserRepo(initSize),
+		PostRepo:       NewPostRepo(initSize),
+		CommentRepo:    NewCommentRepo(initSize),
+		AttachmentRepo: NewAttachmentRepo(initSize),
 	}
 }
@@ -3,21 +3,31 @@ package inmemory
 import (
 	"app/internal/entity"
 	"app/internal/repository"
+	"bytes"
 	"context"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PostRepo struct {
-	posts map[uuid.UUID]entity.Post
-	mu    sync.RWMutex
+	posts     map[uuid.UUID]entity.Post
+	userIndex map[uuid.UUID][]uuid.UUID
+
+	revisions     map[uuid.UUID]entity.PostRevision
+	revisionIndex map[uuid.UUID][]uuid.UUID
+
+	mu sync.RWMutex
 }
 
 func NewPostRepo(initSize int) *PostRepo {
 	return &PostRepo{
-		posts: make(map[uuid.UUID]entity.Post, initSize),
+		posts:         make(map[uuid.UUID]entity.Post, initSize),
+		userIndex:     make(map[uuid.UUID][]uuid.UUID, initSize),
+		revisions:     make(map[uuid.UUID]entity.PostRevision, initSize),
+		revisionIndex: make(map[uuid.UUID][]uuid.UUID, initSize),
 	}
 }
 
@@ -28,6 +38,9 @@ func (r *PostRepo) Create(ctx context.Context, post *entity.Post) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if _, exists := r.posts[post.Id]; !exists {
+		r.userIndex[post.UserId] = append(r.userIndex[post.UserId], post.Id)
+	}
 	r.posts[post.Id] = *post
 	return nil
 }
@@ -46,7 +59,7 @@ func (r *PostRepo) GetOneById(ctx context.Context, id uuid.UUID) (*entity.Post,
 	return &post, nil
 }
 
-func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy repository.SortBy) ([]entity.Post, error) {
+func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy repository.SortBy, viewerId *uuid.UUID) ([]entity.Post, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -60,21 +73,23 @@ func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy reposi
 	}
 
 	for _, post := range r.posts {
-		allPosts = append(allPosts, post)
+		if post.IsPublished() || (viewerId != nil && post.UserId == *viewerId) {
+			allPosts = append(allPosts, post)
+		}
 	}
 
 	switch sortBy {
 	case repository.SortByNewest:
 		sort.Slice(allPosts, func(i, j int) bool {
-			return allPosts[i].CreatedAt.After(allPosts[j].CreatedAt)
+			return feedTime(allPosts[i]).After(feedTime(allPosts[j]))
 		})
 	case repository.SortByOldest:
 		sort.Slice(allPosts, func(i, j int) bool {
-			return allPosts[i].CreatedAt.Before(allPosts[j].CreatedAt)
+			return feedTime(allPosts[i]).Before(feedTime(allPosts[j]))
 		})
 	default:
 		sort.Slice(allPosts, func(i, j int) bool {
-			return allPosts[i].CreatedAt.After(allPosts[j].CreatedAt)
+			return feedTime(allPosts[i]).After(feedTime(allPosts[j]))
 		})
 	}
 
@@ -97,6 +112,154 @@ func (r *PostRepo) Update(ctx context.Context, post *entity.Post) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if previous, exists := r.posts[post.Id]; exists && !previous.HasSameText(post) {
+		revision := entity.NewPostRevision(&previous)
+		r.revisions[revision.Id] = *revision
+		r.revisionIndex[post.Id] = append(r.revisionIndex[post.Id], revision.Id)
+	}
+
 	r.posts[post.Id] = *post
 	return nil
 }
+
+func (r *PostRepo) GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID, includeUnpublished bool) ([]entity.Post, error) {
+	if err := ctx.Err(); err != nil {
+		return []entity.Post{}, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if limit <= 0 {
+		return []entity.Post{}, nil
+	}
+
+	postIds := r.userIndex[userId]
+	userPosts := make([]entity.Post, 0, len(postIds))
+	for _, id := range postIds {
+		if post := r.posts[id]; includeUnpublished || post.IsPublished() {
+			userPosts = append(userPosts, post)
+		}
+	}
+
+	// Newest first, ties broken by id like ORDER BY created_at DESC, id DESC
+	sort.Slice(userPosts, func(i, j int) bool {
+		if !userPosts[i].CreatedAt.Equal(userPosts[j].CreatedAt) {
+			return userPosts[i].CreatedAt.After(userPosts[j].CreatedAt)
+		}
+		return bytes.Compare(userPosts[i].Id[:], userPosts[j].Id[:]) > 0
+	})
+
+	start := 0
+	if after != nil {
+		start = len(userPosts)
+		for ind, post := range userPosts {
+			if post.Id == *after {
+				start = ind + 1
+				break
+			}
+		}
+	}
+
+	end := start + limit
+	if end > len(userPosts) {
+		end = len(userPosts)
+	}
+
+	return userPosts[start:end], nil
+}
+
+func (r *PostRepo) CountByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, id := range r.userIndex[userId] {
+		if post := r.posts[id]; post.IsPublished() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *PostRepo) PublishDue(ctx context.Context, now time.Time) ([]entity.Post, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := make([]entity.Post, 0)
+	for id, post := range r.posts {
+		if post.Status != entity.PostStatusScheduled || post.PublishAt.After(now) {
+			continue
+		}
+		post.Status = entity.PostStatusPublished
+		r.posts[id] = post
+		published = append(published, post)
+	}
+
+	sort.Slice(published, func(i, j int) bool {
+		return published[i].PublishAt.Before(*published[j].PublishAt)
+	})
+
+	return published, nil
+}
+
+func (r *PostRepo) GetRevisions(ctx context.Context, postId uuid.UUID, limit int, after *uuid.UUID) ([]entity.PostRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if limit <= 0 {
+		return []entity.PostRevision{}, nil
+	}
+
+	// The index is in insertion order, so walk it backwards for newest first.
+	ids := r.revisionIndex[postId]
+	start := len(ids) - 1
+	if after != nil {
+		start = -1
+		for ind := len(ids) - 1; ind >= 0; ind-- {
+			if ids[ind] == *after {
+				start = ind - 1
+				break
+			}
+		}
+	}
+
+	revisions := make([]entity.PostRevision, 0, limit)
+	for ind := start; ind >= 0 && len(revisions) < limit; ind-- {
+		revisions = append(revisions, r.revisions[ids[ind]])
+	}
+
+	return revisions, nil
+}
+
+func (r *PostRepo) GetRevision(ctx context.Context, id uuid.UUID) (*entity.PostRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, repository.ErrContextCanceled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revision, exists := r.revisions[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	return &revision, nil
+}
+
+// feedTime orders published posts by the moment they went public, so that
+// a scheduled post shows up at the top of the feed rather than at the time
+// its draft was created.
+func feedTime(post entity.Post) time.Time {
+	if post.PublishAt != nil {
+		return *post.PublishAt
+	}
+	return post.CreatedAt
+}
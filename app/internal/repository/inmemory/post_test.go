@@ -25,6 +25,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 		Title:         "First Post",
 		Content:       "Short content",
 		IsCommentable: true,
+		Status:        entity.PostStatusPublished,
 		CreatedAt:     now.Add(-2 * time.Hour),
 	}
 	post2 := entity.Post{
@@ -33,6 +34,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 		Title:         "Second Post",
 		Content:       "Medium content length",
 		IsCommentable: false,
+		Status:        entity.PostStatusPublished,
 		CreatedAt:     now.Add(-1 * time.Hour),
 	}
 	post3 := entity.Post{
@@ -41,6 +43,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 		Title:         "Third Post",
 		Content:       "Very long content for this post",
 		IsCommentable: true,
+		Status:        entity.PostStatusPublished,
 		CreatedAt:     now,
 	}
 
@@ -96,7 +99,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 		_ = repo.Create(ctx, &post3)
 
 		t.Run("default sorting (newest first)", func(t *testing.T) {
-			posts, err := repo.GetMany(ctx, 10, 0, repository.SortByNewest)
+			posts, err := repo.GetMany(ctx, 10, 0, repository.SortByNewest, nil)
 			assert.NoError(t, err)
 			assert.Len(t, posts, 3)
 			assert.Equal(t, post3.Id, posts[0].Id)
@@ -105,7 +108,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 		})
 
 		t.Run("oldest first", func(t *testing.T) {
-			posts, err := repo.GetMany(ctx, 10, 0, repository.SortByOldest)
+			posts, err := repo.GetMany(ctx, 10, 0, repository.SortByOldest, nil)
 			assert.NoError(t, err)
 			assert.Len(t, posts, 3)
 			assert.Equal(t, post1.Id, posts[0].Id)
@@ -114,7 +117,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 		})
 
 		t.Run("top (by content length)", func(t *testing.T) {
-			posts, err := repo.GetMany(ctx, 10, 0, repository.SortByTop)
+			posts, err := repo.GetMany(ctx, 10, 0, repository.SortByTop, nil)
 			assert.NoError(t, err)
 			assert.Len(t, posts, 3)
 			assert.Equal(t, post3.Id, posts[0].Id)
@@ -124,33 +127,233 @@ func TestInMemoryPostRepo(t *testing.T) {
 
 		t.Run("pagination", func(t *testing.T) {
 			t.Run("limit", func(t *testing.T) {
-				posts, err := repo.GetMany(ctx, 2, 0, repository.SortByNewest)
+				posts, err := repo.GetMany(ctx, 2, 0, repository.SortByNewest, nil)
 				assert.NoError(t, err)
 				assert.Len(t, posts, 2)
 			})
 
 			t.Run("offset", func(t *testing.T) {
-				posts, err := repo.GetMany(ctx, 1, 1, repository.SortByNewest)
+				posts, err := repo.GetMany(ctx, 1, 1, repository.SortByNewest, nil)
 				assert.NoError(t, err)
 				assert.Len(t, posts, 1)
 				assert.Equal(t, post2.Id, posts[0].Id)
 			})
 
 			t.Run("offset out of range", func(t *testing.T) {
-				posts, err := repo.GetMany(ctx, 10, 10, repository.SortByNewest)
+				posts, err := repo.GetMany(ctx, 10, 10, repository.SortByNewest, nil)
 				assert.NoError(t, err)
 				assert.Empty(t, posts)
 			})
 		})
 
 		t.Run("limit 0", func(t *testing.T) {
-			posts, err := repo.GetMany(ctx, 0, 0, repository.SortByNewest)
+			posts, err := repo.GetMany(ctx, 0, 0, repository.SortByNewest, nil)
 			assert.NoError(t, err)
 			assert.Empty(t, posts)
 		})
 
 		t.Run("canceled context", func(t *testing.T) {
-			_, err := repo.GetMany(canceledCtx, 10, 0, repository.SortByNewest)
+			_, err := repo.GetMany(canceledCtx, 10, 0, repository.SortByNewest, nil)
+			assert.ErrorIs(t, err, repository.ErrContextCanceled)
+		})
+	})
+
+	t.Run("GetByUser", func(t *testing.T) {
+		userRepo := inmemory.NewPostRepo(10)
+		authorId := uuid.New()
+		authorPosts := []entity.Post{post1, post2, post3}
+		for i := range authorPosts {
+			authorPosts[i].UserId = authorId
+			_ = userRepo.Create(ctx, &authorPosts[i])
+		}
+
+		t.Run("newest first", func(t *testing.T) {
+			posts, err := userRepo.GetByUser(ctx, authorId, 2, nil, false)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 2)
+			assert.Equal(t, post3.Id, posts[0].Id)
+			assert.Equal(t, post2.Id, posts[1].Id)
+		})
+
+		t.Run("after cursor", func(t *testing.T) {
+			posts, err := userRepo.GetByUser(ctx, authorId, 2, &post2.Id, false)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 1)
+			assert.Equal(t, post1.Id, posts[0].Id)
+		})
+
+		t.Run("other user", func(t *testing.T) {
+			posts, err := userRepo.GetByUser(ctx, uuid.New(), 10, nil, false)
+			assert.NoError(t, err)
+			assert.Empty(t, posts)
+		})
+
+		t.Run("count", func(t *testing.T) {
+			count, err := userRepo.CountByUser(ctx, authorId)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, count)
+		})
+
+		t.Run("unpublished posts", func(t *testing.T) {
+			draft := entity.Post{
+				Id:        uuid.New(),
+				UserId:    authorId,
+				Title:     "Draft",
+				Status:    entity.PostStatusDraft,
+				CreatedAt: now.Add(time.Hour),
+			}
+			_ = userRepo.Create(ctx, &draft)
+
+			posts, err := userRepo.GetByUser(ctx, authorId, 10, nil, false)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 3)
+
+			posts, err = userRepo.GetByUser(ctx, authorId, 10, nil, true)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 4)
+			assert.Equal(t, draft.Id, posts[0].Id)
+
+			count, err := userRepo.CountByUser(ctx, authorId)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, count)
+		})
+
+		t.Run("same created_at ordered by id", func(t *testing.T) {
+			sameRepo := inmemory.NewPostRepo(10)
+			low := post1
+			low.Id = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+			low.UserId = authorId
+			high := low
+			high.Id = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+			_ = sameRepo.Create(ctx, &low)
+			_ = sameRepo.Create(ctx, &high)
+
+			posts, err := sameRepo.GetByUser(ctx, authorId, 1, nil, false)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 1)
+			assert.Equal(t, high.Id, posts[0].Id)
+
+			posts, err = sameRepo.GetByUser(ctx, authorId, 1, &high.Id, false)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 1)
+			assert.Equal(t, low.Id, posts[0].Id)
+		})
+
+		t.Run("canceled context", func(t *testing.T) {
+			_, err := userRepo.GetByUser(canceledCtx, authorId, 10, nil, false)
+			assert.ErrorIs(t, err, repository.ErrContextCanceled)
+		})
+	})
+
+	t.Run("Drafts", func(t *testing.T) {
+		draftRepo := inmemory.NewPostRepo(10)
+		authorId := uuid.New()
+		published := post1
+		draft := entity.Post{
+			Id:        uuid.New(),
+			UserId:    authorId,
+			Title:     "Draft",
+			Status:    entity.PostStatusDraft,
+			CreatedAt: now,
+		}
+		_ = draftRepo.Create(ctx, &published)
+		_ = draftRepo.Create(ctx, &draft)
+
+		t.Run("hidden from others", func(t *testing.T) {
+			posts, err := draftRepo.GetMany(ctx, 10, 0, repository.SortByNewest, nil)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 1)
+			assert.Equal(t, published.Id, posts[0].Id)
+
+			otherId := uuid.New()
+			posts, err = draftRepo.GetMany(ctx, 10, 0, repository.SortByNewest, &otherId)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 1)
+		})
+
+		t.Run("visible to author", func(t *testing.T) {
+			posts, err := draftRepo.GetMany(ctx, 10, 0, repository.SortByNewest, &authorId)
+			assert.NoError(t, err)
+			assert.Len(t, posts, 2)
+			assert.Equal(t, draft.Id, posts[0].Id)
+		})
+	})
+
+	t.Run("PublishDue", func(t *testing.T) {
+		scheduleRepo := inmemory.NewPostRepo(10)
+		dueAt := now.Add(-time.Minute)
+		laterAt := now.Add(time.Hour)
+		due := entity.Post{Id: uuid.New(), Status: entity.PostStatusScheduled, PublishAt: &dueAt, CreatedAt: now}
+		later := entity.Post{Id: uuid.New(), Status: entity.PostStatusScheduled, PublishAt: &laterAt, CreatedAt: now}
+		_ = scheduleRepo.Create(ctx, &due)
+		_ = scheduleRepo.Create(ctx, &later)
+
+		posts, err := scheduleRepo.PublishDue(ctx, now)
+		assert.NoError(t, err)
+		assert.Len(t, posts, 1)
+		assert.Equal(t, due.Id, posts[0].Id)
+		assert.Equal(t, entity.PostStatusPublished, posts[0].Status)
+
+		t.Run("published only once", func(t *testing.T) {
+			posts, err := scheduleRepo.PublishDue(ctx, now)
+			assert.NoError(t, err)
+			assert.Empty(t, posts)
+		})
+
+		t.Run("stored as published", func(t *testing.T) {
+			result, err := scheduleRepo.GetOneById(ctx, due.Id)
+			assert.NoError(t, err)
+			assert.True(t, result.IsPublished())
+
+			result, err = scheduleRepo.GetOneById(ctx, later.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, entity.PostStatusScheduled, result.Status)
+		})
+
+		t.Run("canceled context", func(t *testing.T) {
+			_, err := scheduleRepo.PublishDue(canceledCtx, now)
+			assert.ErrorIs(t, err, repository.ErrContextCanceled)
+		})
+	})
+
+	t.Run("Revisions", func(t *testing.T) {
+		revisionRepo := inmemory.NewPostRepo(10)
+		post := entity.Post{Id: uuid.New(), Title: "v1", Content: "first", Status: entity.PostStatusPublished, CreatedAt: now}
+		_ = revisionRepo.Create(ctx, &post)
+
+		post.Title, post.Content = "v2", "second"
+		assert.NoError(t, revisionRepo.Update(ctx, &post))
+		post.IsCommentable = true
+		assert.NoError(t, revisionRepo.Update(ctx, &post))
+		post.Title, post.Content = "v3", "third"
+		assert.NoError(t, revisionRepo.Update(ctx, &post))
+
+		revisions, err := revisionRepo.GetRevisions(ctx, post.Id, 10, nil)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 2, "updates that keep the text should not create revisions")
+		assert.Equal(t, "v2", revisions[0].Title)
+		assert.Equal(t, "first", revisions[1].Content)
+
+		t.Run("after cursor", func(t *testing.T) {
+			result, err := revisionRepo.GetRevisions(ctx, post.Id, 10, &revisions[0].Id)
+			assert.NoError(t, err)
+			assert.Equal(t, []entity.PostRevision{revisions[1]}, result)
+		})
+
+		t.Run("get one", func(t *testing.T) {
+			result, err := revisionRepo.GetRevision(ctx, revisions[1].Id)
+			assert.NoError(t, err)
+			assert.Equal(t, post.Id, result.PostId)
+			assert.Equal(t, "v1", result.Title)
+		})
+
+		t.Run("not found", func(t *testing.T) {
+			_, err := revisionRepo.GetRevision(ctx, uuid.New())
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+		})
+
+		t.Run("canceled context", func(t *testing.T) {
+			_, err := revisionRepo.GetRevisions(canceledCtx, post.Id, 10, nil)
 			assert.ErrorIs(t, err, repository.ErrContextCanceled)
 		})
 	})
@@ -179,7 +382,7 @@ func TestInMemoryPostRepo(t *testing.T) {
 						return
 					default:
 						_, _ = repo.GetOneById(ctx, post1.Id)
-						_, _ = repo.GetMany(ctx, 2, 0, repository.SortByNewest)
+						_, _ = repo.GetMany(ctx, 2, 0, repository.SortByNewest, nil)
 					}
 				}
 			}()
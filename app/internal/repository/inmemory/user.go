@@ -33,6 +33,19 @@ func (repo *UserRepo) Create(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+func (repo *UserRepo) Update(ctx context.Context, user *entity.User) error {
+	if err := ctx.Err(); err != nil {
+		return repository.ErrContextCanceled
+	}
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	if _, exists := repo.users[user.Id]; !exists {
+		return repository.ErrNotFound
+	}
+	repo.users[user.Id] = *user
+	return nil
+}
+
 func (repo *UserRepo) GetOneById(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, repository.ErrContextCanceled
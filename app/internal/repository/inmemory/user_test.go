@@ -44,6 +44,30 @@ func TestInMemoryUserRepo(t *testing.T) {
 				assert.ErrorIs(t, err, repository.ErrContextCanceled)
 			},
 		},
+		{
+			name: "Update/success",
+			run: func(t *testing.T, repo *inmemory.UserRepo) {
+				_ = repo.Create(context.Background(), &user1)
+				updated := user1
+				updated.DisplayName = "User One"
+				updated.Bio = "Bio"
+
+				err := repo.Update(context.Background(), &updated)
+				assert.NoError(t, err)
+
+				result, err := repo.GetOneById(context.Background(), user1.Id)
+				assert.NoError(t, err)
+				assert.Equal(t, "User One", result.DisplayName)
+				assert.Equal(t, "Bio", result.Bio)
+			},
+		},
+		{
+			name: "Update/not found",
+			run: func(t *testing.T, repo *inmemory.UserRepo) {
+				err := repo.Update(context.Background(), &user2)
+				assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
 		{
 			name: "GetOneById/success",
 			run: func(t *testing.T, repo *inmemory.UserRepo) {
@@ -9,6 +9,7 @@ import (
 	repository "app/internal/repository"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -96,6 +97,20 @@ func (mr *MockUserRepoMockRecorder) GetOneByUsername(ctx, username interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneByUsername", reflect.TypeOf((*MockUserRepo)(nil).GetOneByUsername), ctx, username)
 }
 
+// Update mocks base method.
+func (m *MockUserRepo) Update(ctx context.Context, user *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserRepoMockRecorder) Update(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepo)(nil).Update), ctx, user)
+}
+
 // MockPostRepo is a mock of PostRepo interface.
 type MockPostRepo struct {
 	ctrl     *gomock.Controller
@@ -119,6 +134,21 @@ func (m *MockPostRepo) EXPECT() *MockPostRepoMockRecorder {
 	return m.recorder
 }
 
+// CountByUser mocks base method.
+func (m *MockPostRepo) CountByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", ctx, userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockPostRepoMockRecorder) CountByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockPostRepo)(nil).CountByUser), ctx, userId)
+}
+
 // Create mocks base method.
 func (m *MockPostRepo) Create(ctx context.Context, post *entity.Post) error {
 	m.ctrl.T.Helper()
@@ -133,19 +163,34 @@ func (mr *MockPostRepoMockRecorder) Create(ctx, post interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPostRepo)(nil).Create), ctx, post)
 }
 
+// GetByUser mocks base method.
+func (m *MockPostRepo) GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID, includeUnpublished bool) ([]entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userId, limit, after, includeUnpublished)
+	ret0, _ := ret[0].([]entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockPostRepoMockRecorder) GetByUser(ctx, userId, limit, after, includeUnpublished interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockPostRepo)(nil).GetByUser), ctx, userId, limit, after, includeUnpublished)
+}
+
 // GetMany mocks base method.
-func (m *MockPostRepo) GetMany(ctx context.Context, limit, offset int, sortBy repository.SortBy) ([]entity.Post, error) {
+func (m *MockPostRepo) GetMany(ctx context.Context, limit, offset int, sortBy repository.SortBy, viewerId *uuid.UUID) ([]entity.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMany", ctx, limit, offset, sortBy)
+	ret := m.ctrl.Call(m, "GetMany", ctx, limit, offset, sortBy, viewerId)
 	ret0, _ := ret[0].([]entity.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMany indicates an expected call of GetMany.
-func (mr *MockPostRepoMockRecorder) GetMany(ctx, limit, offset, sortBy interface{}) *gomock.Call {
+func (mr *MockPostRepoMockRecorder) GetMany(ctx, limit, offset, sortBy, viewerId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockPostRepo)(nil).GetMany), ctx, limit, offset, sortBy)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockPostRepo)(nil).GetMany), ctx, limit, offset, sortBy, viewerId)
 }
 
 // GetOneById mocks base method.
@@ -163,6 +208,51 @@ func (mr *MockPostRepoMockRecorder) GetOneById(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneById", reflect.TypeOf((*MockPostRepo)(nil).GetOneById), ctx, id)
 }
 
+// GetRevision mocks base method.
+func (m *MockPostRepo) GetRevision(ctx context.Context, id uuid.UUID) (*entity.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id)
+	ret0, _ := ret[0].(*entity.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockPostRepoMockRecorder) GetRevision(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockPostRepo)(nil).GetRevision), ctx, id)
+}
+
+// GetRevisions mocks base method.
+func (m *MockPostRepo) GetRevisions(ctx context.Context, postId uuid.UUID, limit int, after *uuid.UUID) ([]entity.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, postId, limit, after)
+	ret0, _ := ret[0].([]entity.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockPostRepoMockRecorder) GetRevisions(ctx, postId, limit, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockPostRepo)(nil).GetRevisions), ctx, postId, limit, after)
+}
+
+// PublishDue mocks base method.
+func (m *MockPostRepo) PublishDue(ctx context.Context, now time.Time) ([]entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", ctx, now)
+	ret0, _ := ret[0].([]entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockPostRepoMockRecorder) PublishDue(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockPostRepo)(nil).PublishDue), ctx, now)
+}
+
 // Update mocks base method.
 func (m *MockPostRepo) Update(ctx context.Context, post *entity.Post) error {
 	m.ctrl.T.Helper()
@@ -200,6 +290,21 @@ func (m *MockCommentRepo) EXPECT() *MockCommentRepoMockRecorder {
 	return m.recorder
 }
 
+// CountByUser mocks base method.
+func (m *MockCommentRepo) CountByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", ctx, userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockCommentRepoMockRecorder) CountByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockCommentRepo)(nil).CountByUser), ctx, userId)
+}
+
 // Create mocks base method.
 func (m *MockCommentRepo) Create(ctx context.Context, comment *entity.Comment) error {
 	m.ctrl.T.Helper()
@@ -214,6 +319,21 @@ func (mr *MockCommentRepoMockRecorder) Create(ctx, comment interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentRepo)(nil).Create), ctx, comment)
 }
 
+// GetAncestors mocks base method.
+func (m *MockCommentRepo) GetAncestors(ctx context.Context, commentId uuid.UUID) ([]entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAncestors", ctx, commentId)
+	ret0, _ := ret[0].([]entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAncestors indicates an expected call of GetAncestors.
+func (mr *MockCommentRepoMockRecorder) GetAncestors(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAncestors", reflect.TypeOf((*MockCommentRepo)(nil).GetAncestors), ctx, commentId)
+}
+
 // GetByPost mocks base method.
 func (m *MockCommentRepo) GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]entity.Comment, error) {
 	m.ctrl.T.Helper()
@@ -229,6 +349,21 @@ func (mr *MockCommentRepoMockRecorder) GetByPost(ctx, postId, limit, offset inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPost", reflect.TypeOf((*MockCommentRepo)(nil).GetByPost), ctx, postId, limit, offset)
 }
 
+// GetByUser mocks base method.
+func (m *MockCommentRepo) GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID) ([]entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userId, limit, after)
+	ret0, _ := ret[0].([]entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockCommentRepoMockRecorder) GetByUser(ctx, userId, limit, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockCommentRepo)(nil).GetByUser), ctx, userId, limit, after)
+}
+
 // GetCommentReplies mocks base method.
 func (m *MockCommentRepo) GetCommentReplies(ctx context.Context, parentId uuid.UUID, limit, offset int) ([]entity.Comment, error) {
 	m.ctrl.T.Helper()
@@ -244,6 +379,36 @@ func (mr *MockCommentRepoMockRecorder) GetCommentReplies(ctx, parentId, limit, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentReplies", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentReplies), ctx, parentId, limit, offset)
 }
 
+// GetCrossPostReplies mocks base method.
+func (m *MockCommentRepo) GetCrossPostReplies(ctx context.Context) ([]entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCrossPostReplies", ctx)
+	ret0, _ := ret[0].([]entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCrossPostReplies indicates an expected call of GetCrossPostReplies.
+func (mr *MockCommentRepoMockRecorder) GetCrossPostReplies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCrossPostReplies", reflect.TypeOf((*MockCommentRepo)(nil).GetCrossPostReplies), ctx)
+}
+
+// GetHistory mocks base method.
+func (m *MockCommentRepo) GetHistory(ctx context.Context, commentId uuid.UUID) ([]entity.CommentEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, commentId)
+	ret0, _ := ret[0].([]entity.CommentEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockCommentRepoMockRecorder) GetHistory(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockCommentRepo)(nil).GetHistory), ctx, commentId)
+}
+
 // GetOneById mocks base method.
 func (m *MockCommentRepo) GetOneById(ctx context.Context, commentId uuid.UUID) (*entity.Comment, error) {
 	m.ctrl.T.Helper()
@@ -258,3 +423,98 @@ func (mr *MockCommentRepoMockRecorder) GetOneById(ctx, commentId interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneById", reflect.TypeOf((*MockCommentRepo)(nil).GetOneById), ctx, commentId)
 }
+
+// IsThreadLocked mocks base method.
+func (m *MockCommentRepo) IsThreadLocked(ctx context.Context, commentId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsThreadLocked", ctx, commentId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsThreadLocked indicates an expected call of IsThreadLocked.
+func (mr *MockCommentRepoMockRecorder) IsThreadLocked(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsThreadLocked", reflect.TypeOf((*MockCommentRepo)(nil).IsThreadLocked), ctx, commentId)
+}
+
+// SetLocked mocks base method.
+func (m *MockCommentRepo) SetLocked(ctx context.Context, commentId uuid.UUID, locked bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocked", ctx, commentId, locked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocked indicates an expected call of SetLocked.
+func (mr *MockCommentRepoMockRecorder) SetLocked(ctx, commentId, locked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocked", reflect.TypeOf((*MockCommentRepo)(nil).SetLocked), ctx, commentId, locked)
+}
+
+// Update mocks base method.
+func (m *MockCommentRepo) Update(ctx context.Context, comment *entity.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCommentRepoMockRecorder) Update(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentRepo)(nil).Update), ctx, comment)
+}
+
+// MockAttachmentRepo is a mock of AttachmentRepo interface.
+type MockAttachmentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentRepoMockRecorder
+}
+
+// MockAttachmentRepoMockRecorder is the mock recorder for MockAttachmentRepo.
+type MockAttachmentRepoMockRecorder struct {
+	mock *MockAttachmentRepo
+}
+
+// NewMockAttachmentRepo creates a new mock instance.
+func NewMockAttachmentRepo(ctrl *gomock.Controller) *MockAttachmentRepo {
+	mock := &MockAttachmentRepo{ctrl: ctrl}
+	mock.recorder = &MockAttachmentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentRepo) EXPECT() *MockAttachmentRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAttachmentRepo) Create(ctx context.Context, attachment *entity.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentRepoMockRecorder) Create(ctx, attachment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachmentRepo)(nil).Create), ctx, attachment)
+}
+
+// GetByOwner mocks base method.
+func (m *MockAttachmentRepo) GetByOwner(ctx context.Context, ownerType entity.AttachmentOwner, ownerId uuid.UUID) ([]entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOwner", ctx, ownerType, ownerId)
+	ret0, _ := ret[0].([]entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOwner indicates an expected call of GetByOwner.
+func (mr *MockAttachmentRepoMockRecorder) GetByOwner(ctx, ownerType, ownerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOwner", reflect.TypeOf((*MockAttachmentRepo)(nil).GetByOwner), ctx, ownerType, ownerId)
+}
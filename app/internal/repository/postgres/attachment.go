@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"app/internal/entity"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentRepo struct {
+	db Database
+}
+
+func NewAttachmentRepo(db Database) *AttachmentRepo {
+	return &AttachmentRepo{db: db}
+}
+
+func (r *AttachmentRepo) Create(ctx context.Context, attachment *entity.Attachment) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO attachments (id, owner_type, owner_id, user_id, filename, content_type, size, blob_key, thumbnail_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.Exec(ctx, query,
+		attachment.Id, attachment.OwnerType, attachment.OwnerId, attachment.UserId, attachment.Filename,
+		attachment.ContentType, attachment.Size, attachment.BlobKey, attachment.ThumbnailKey, attachment.CreatedAt)
+	return err
+}
+
+func (r *AttachmentRepo) GetByOwner(ctx context.Context, ownerType entity.AttachmentOwner, ownerId uuid.UUID) ([]entity.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, owner_type, owner_id, user_id, filename, content_type, size, blob_key, thumbnail_key, created_at
+		FROM attachments
+		WHERE owner_type = $1 AND owner_id = $2
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(ctx, query, ownerType, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]entity.Attachment, 0)
+	for rows.Next() {
+		var attachment entity.Attachment
+		if err := rows.Scan(
+			&attachment.Id, &attachment.OwnerType, &attachment.OwnerId, &attachment.UserId, &attachment.Filename,
+			&attachment.ContentType, &attachment.Size, &attachment.BlobKey, &attachment.ThumbnailKey, &attachment.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
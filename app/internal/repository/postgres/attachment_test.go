@@ -0,0 +1,76 @@
+package postgres_test
+
+import (
+	"app/internal/entity"
+	"app/internal/repository/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentRepo(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := postgres.NewAttachmentRepo(mock)
+
+	attachment := entity.Attachment{
+		Id:           uuid.New(),
+		OwnerType:    entity.AttachmentOwnerComment,
+		OwnerId:      uuid.New(),
+		UserId:       uuid.New(),
+		Filename:     "photo.png",
+		ContentType:  "image/png",
+		Size:         2048,
+		BlobKey:      "attachments/comments/photo.png",
+		ThumbnailKey: "attachments/comments/photo_thumb.png",
+		CreatedAt:    time.Now(),
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO attachments").
+			WithArgs(attachment.Id, attachment.OwnerType, attachment.OwnerId, attachment.UserId, attachment.Filename,
+				attachment.ContentType, attachment.Size, attachment.BlobKey, attachment.ThumbnailKey, attachment.CreatedAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		err := repo.Create(context.Background(), &attachment)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetByOwner", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM attachments WHERE owner_type = (.+) AND owner_id =").
+			WithArgs(entity.AttachmentOwnerComment, attachment.OwnerId).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "owner_type", "owner_id", "user_id", "filename", "content_type", "size", "blob_key", "thumbnail_key", "created_at",
+			}).AddRow(
+				attachment.Id, attachment.OwnerType, attachment.OwnerId, attachment.UserId, attachment.Filename,
+				attachment.ContentType, attachment.Size, attachment.BlobKey, attachment.ThumbnailKey, attachment.CreatedAt,
+			))
+
+		result, err := repo.GetByOwner(context.Background(), entity.AttachmentOwnerComment, attachment.OwnerId)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Attachment{attachment}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetByOwner empty", func(t *testing.T) {
+		ownerId := uuid.New()
+		mock.ExpectQuery("SELECT (.+) FROM attachments").
+			WithArgs(entity.AttachmentOwnerPost, ownerId).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "owner_type", "owner_id", "user_id", "filename", "content_type", "size", "blob_key", "thumbnail_key", "created_at",
+			}))
+
+		result, err := repo.GetByOwner(context.Background(), entity.AttachmentOwnerPost, ownerId)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -8,9 +8,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 )
 
+// parentSamePostConstraint keeps replies on the same post as their parent.
+const parentSamePostConstraint = "comments_parent_same_post_fkey"
+
 type CommentRepo struct {
 	db Database
 }
@@ -25,7 +29,7 @@ func (r *CommentRepo) GetOneById(ctx context.Context, commentId uuid.UUID) (*ent
 
 	var comment entity.Comment
 	query := `
-        SELECT id, user_id, post_id, parent_id, content, created_at
+        SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth
         FROM comments
         WHERE id = $1
     `
@@ -37,6 +41,10 @@ func (r *CommentRepo) GetOneById(ctx context.Context, commentId uuid.UUID) (*ent
 		&comment.ParentId,
 		&comment.Content,
 		&comment.CreatedAt,
+		&comment.EditedAt,
+		&comment.Locked,
+		&comment.Path,
+		&comment.Depth,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -51,16 +59,22 @@ func (r *CommentRepo) GetOneById(ctx context.Context, commentId uuid.UUID) (*ent
 
 func (r *CommentRepo) Create(ctx context.Context, comment *entity.Comment) error {
 	query := `
-		INSERT INTO comments (id, user_id, post_id, parent_id, content, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO comments (id, user_id, post_id, parent_id, content, created_at, path, depth)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	_, err := r.db.Exec(ctx, query, comment.Id, comment.UserId, comment.PostId, comment.ParentId, comment.Content, comment.CreatedAt)
+	_, err := r.db.Exec(ctx, query,
+		comment.Id, comment.UserId, comment.PostId, comment.ParentId, comment.Content, comment.CreatedAt, comment.Path, comment.Depth)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == parentSamePostConstraint {
+		return repository.ErrParentOnDifferentPost
+	}
 	return err
 }
 
 func (r *CommentRepo) GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]entity.Comment, error) {
 	query := `
-        SELECT id, user_id, post_id, parent_id, content, created_at
+        SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth
         FROM comments
         WHERE post_id = $1 AND parent_id IS NULL
         ORDER BY created_at DESC
@@ -77,7 +91,7 @@ func (r *CommentRepo) GetByPost(ctx context.Context, postId uuid.UUID, limit, of
 	for rows.Next() {
 		var comment entity.Comment
 		if err := rows.Scan(
-			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content, &comment.CreatedAt); err != nil {
+			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content, &comment.CreatedAt, &comment.EditedAt, &comment.Locked, &comment.Path, &comment.Depth); err != nil {
 			return nil, err
 		}
 		comments = append(comments, comment)
@@ -88,7 +102,7 @@ func (r *CommentRepo) GetByPost(ctx context.Context, postId uuid.UUID, limit, of
 
 func (r *CommentRepo) GetCommentReplies(ctx context.Context, parentId uuid.UUID, limit, offset int) ([]entity.Comment, error) {
 	query := `
-        SELECT id, user_id, post_id, parent_id, content, created_at
+        SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth
         FROM comments
         WHERE parent_id = $1
         ORDER BY created_at ASC
@@ -105,7 +119,39 @@ func (r *CommentRepo) GetCommentReplies(ctx context.Context, parentId uuid.UUID,
 	for rows.Next() {
 		var comment entity.Comment
 		if err := rows.Scan(
-			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content, &comment.CreatedAt); err != nil {
+			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content, &comment.CreatedAt, &comment.EditedAt, &comment.Locked, &comment.Path, &comment.Depth); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func (r *CommentRepo) GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID) ([]entity.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth
+        FROM comments
+        WHERE user_id = $1
+          AND ($3::uuid IS NULL OR (created_at, id) < (SELECT created_at, id FROM comments WHERE id = $3))
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2
+    `
+
+	rows, err := r.db.Query(ctx, query, userId, limit, after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []entity.Comment
+	for rows.Next() {
+		var comment entity.Comment
+		if err := rows.Scan(
+			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content, &comment.CreatedAt, &comment.EditedAt, &comment.Locked, &comment.Path, &comment.Depth); err != nil {
 			return nil, err
 		}
 		comments = append(comments, comment)
@@ -113,3 +159,173 @@ func (r *CommentRepo) GetCommentReplies(ctx context.Context, parentId uuid.UUID,
 
 	return comments, rows.Err()
 }
+
+func (r *CommentRepo) Update(ctx context.Context, comment *entity.Comment) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// History is append-only: the replaced content is copied in the same
+	// statement as the update and never modified afterwards.
+	query := `
+		WITH previous AS (
+			SELECT id, content FROM comments WHERE id = $1 FOR UPDATE
+		), history AS (
+			INSERT INTO comment_history (id, comment_id, content, created_at)
+			SELECT $4, id, content, $3 FROM previous
+			WHERE content <> $2
+		)
+		UPDATE comments
+		SET content = $2, edited_at = $3
+		WHERE id = $1
+	`
+	result, err := r.db.Exec(ctx, query, comment.Id, comment.Content, comment.EditedAt, uuid.New())
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) GetHistory(ctx context.Context, commentId uuid.UUID) ([]entity.CommentEdit, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, comment_id, content, created_at
+		FROM comment_history
+		WHERE comment_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(ctx, query, commentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]entity.CommentEdit, 0)
+	for rows.Next() {
+		var edit entity.CommentEdit
+		if err := rows.Scan(&edit.Id, &edit.CommentId, &edit.Content, &edit.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, edit)
+	}
+
+	return history, rows.Err()
+}
+
+func (r *CommentRepo) SetLocked(ctx context.Context, commentId uuid.UUID, locked bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.Exec(ctx, `UPDATE comments SET locked = $2 WHERE id = $1`, commentId, locked)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) IsThreadLocked(ctx context.Context, commentId uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// The path holds every ancestor, so one lookup covers the whole chain.
+	query := `
+		SELECT COUNT(c.id) > 0, COALESCE(BOOL_OR(a.locked), FALSE)
+		FROM comments c
+		JOIN comments a ON a.id = c.id OR a.id = ANY(c.path)
+		WHERE c.id = $1
+	`
+	var found, locked bool
+	if err := r.db.QueryRow(ctx, query, commentId).Scan(&found, &locked); err != nil {
+		return false, err
+	}
+	if !found {
+		return false, repository.ErrNotFound
+	}
+
+	return locked, nil
+}
+
+func (r *CommentRepo) GetAncestors(ctx context.Context, commentId uuid.UUID) ([]entity.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT a.id, a.user_id, a.post_id, a.parent_id, a.content, a.created_at, a.edited_at, a.locked, a.path, a.depth
+		FROM comments c
+		JOIN comments a ON a.id = ANY(c.path)
+		WHERE c.id = $1
+		ORDER BY a.depth
+	`
+	rows, err := r.db.Query(ctx, query, commentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ancestors := make([]entity.Comment, 0)
+	for rows.Next() {
+		var comment entity.Comment
+		if err := rows.Scan(
+			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content,
+			&comment.CreatedAt, &comment.EditedAt, &comment.Locked, &comment.Path, &comment.Depth); err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, comment)
+	}
+
+	return ancestors, rows.Err()
+}
+
+func (r *CommentRepo) GetCrossPostReplies(ctx context.Context) ([]entity.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT c.id, c.user_id, c.post_id, c.parent_id, c.content, c.created_at, c.edited_at, c.locked, c.path, c.depth
+		FROM comments c
+		JOIN comments p ON p.id = c.parent_id
+		WHERE p.post_id <> c.post_id
+		ORDER BY c.created_at
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	replies := make([]entity.Comment, 0)
+	for rows.Next() {
+		var comment entity.Comment
+		if err := rows.Scan(
+			&comment.Id, &comment.UserId, &comment.PostId, &comment.ParentId, &comment.Content,
+			&comment.CreatedAt, &comment.EditedAt, &comment.Locked, &comment.Path, &comment.Depth); err != nil {
+			return nil, err
+		}
+		replies = append(replies, comment)
+	}
+
+	return replies, rows.Err()
+}
+
+func (r *CommentRepo) CountByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM comments WHERE user_id = $1`
+	if err := r.db.QueryRow(ctx, query, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -2,12 +2,14 @@ package postgres_test
 
 import (
 	"app/internal/entity"
+	"app/internal/repository"
 	"app/internal/repository/postgres"
 	"context"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,11 +32,11 @@ func TestCommentRepo(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at FROM comments").
+		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth FROM comments").
 			WithArgs(expectedComment.Id).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at", "edited_at", "locked", "path", "depth"}).
 				AddRow(expectedComment.Id, expectedComment.UserId, expectedComment.PostId,
-					expectedComment.ParentId, expectedComment.Content, expectedComment.CreatedAt))
+					expectedComment.ParentId, expectedComment.Content, expectedComment.CreatedAt, expectedComment.EditedAt, expectedComment.Locked, expectedComment.Path, expectedComment.Depth))
 
 		comment, err := repo.GetOneById(context.Background(), expectedComment.Id)
 		assert.NoError(t, err)
@@ -54,7 +56,7 @@ func TestCommentRepo(t *testing.T) {
 
 		mock.ExpectExec("INSERT INTO comments").
 			WithArgs(comment.Id, comment.UserId, comment.PostId, comment.ParentId,
-				comment.Content, comment.CreatedAt).
+				comment.Content, comment.CreatedAt, comment.Path, comment.Depth).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err := repo.Create(context.Background(), comment)
@@ -83,13 +85,13 @@ func TestCommentRepo(t *testing.T) {
 			},
 		}
 
-		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at FROM comments").
+		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth FROM comments").
 			WithArgs(postId, 10, 0).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at", "edited_at", "locked", "path", "depth"}).
 				AddRow(comments[0].Id, comments[0].UserId, comments[0].PostId,
-					comments[0].ParentId, comments[0].Content, comments[0].CreatedAt).
+					comments[0].ParentId, comments[0].Content, comments[0].CreatedAt, comments[0].EditedAt, comments[0].Locked, comments[0].Path, comments[0].Depth).
 				AddRow(comments[1].Id, comments[1].UserId, comments[1].PostId,
-					comments[1].ParentId, comments[1].Content, comments[1].CreatedAt))
+					comments[1].ParentId, comments[1].Content, comments[1].CreatedAt, comments[1].EditedAt, comments[1].Locked, comments[1].Path, comments[1].Depth))
 
 		result, err := repo.GetByPost(context.Background(), postId, 10, 0)
 		assert.NoError(t, err)
@@ -120,13 +122,13 @@ func TestCommentRepo(t *testing.T) {
 			},
 		}
 
-		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at FROM comments").
+		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth FROM comments").
 			WithArgs(parentId, 10, 0).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at", "edited_at", "locked", "path", "depth"}).
 				AddRow(replies[0].Id, replies[0].UserId, replies[0].PostId,
-					replies[0].ParentId, replies[0].Content, replies[0].CreatedAt).
+					replies[0].ParentId, replies[0].Content, replies[0].CreatedAt, replies[0].EditedAt, replies[0].Locked, replies[0].Path, replies[0].Depth).
 				AddRow(replies[1].Id, replies[1].UserId, replies[1].PostId,
-					replies[1].ParentId, replies[1].Content, replies[1].CreatedAt))
+					replies[1].ParentId, replies[1].Content, replies[1].CreatedAt, replies[1].EditedAt, replies[1].Locked, replies[1].Path, replies[1].Depth))
 
 		result, err := repo.GetCommentReplies(context.Background(), parentId, 10, 0)
 		assert.NoError(t, err)
@@ -135,4 +137,206 @@ func TestCommentRepo(t *testing.T) {
 		assert.Equal(t, replies[1].Content, result[1].Content)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("GetByUser", func(t *testing.T) {
+		userId := uuid.New()
+		comment := entity.Comment{
+			Id:        uuid.New(),
+			UserId:    userId,
+			PostId:    uuid.New(),
+			ParentId:  nil,
+			Content:   "Comment 1",
+			CreatedAt: time.Now(),
+		}
+
+		mock.ExpectQuery("SELECT id, user_id, post_id, parent_id, content, created_at, edited_at, locked, path, depth FROM comments WHERE user_id =").
+			WithArgs(userId, 10, (*uuid.UUID)(nil)).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at", "edited_at", "locked", "path", "depth"}).
+				AddRow(comment.Id, comment.UserId, comment.PostId, comment.ParentId, comment.Content, comment.CreatedAt, comment.EditedAt, comment.Locked, comment.Path, comment.Depth))
+
+		result, err := repo.GetByUser(context.Background(), userId, 10, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Comment{comment}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CountByUser", func(t *testing.T) {
+		userId := uuid.New()
+
+		mock.ExpectQuery("SELECT COUNT").
+			WithArgs(userId).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(5))
+
+		count, err := repo.CountByUser(context.Background(), userId)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		editedAt := time.Now()
+		comment := &entity.Comment{
+			Id:       uuid.New(),
+			Content:  "Edited comment",
+			EditedAt: &editedAt,
+		}
+
+		mock.ExpectExec("WITH previous AS (.+) INSERT INTO comment_history (.+) UPDATE comments SET content = \\$2, edited_at = \\$3").
+			WithArgs(comment.Id, comment.Content, comment.EditedAt, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := repo.Update(context.Background(), comment)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update not found", func(t *testing.T) {
+		editedAt := time.Now()
+		comment := &entity.Comment{Id: uuid.New(), Content: "Edited", EditedAt: &editedAt}
+
+		mock.ExpectExec("UPDATE comments").
+			WithArgs(comment.Id, comment.Content, comment.EditedAt, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err := repo.Update(context.Background(), comment)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetHistory", func(t *testing.T) {
+		commentId := uuid.New()
+		edit := entity.CommentEdit{
+			Id:        uuid.New(),
+			CommentId: commentId,
+			Content:   "Original",
+			CreatedAt: time.Now(),
+		}
+
+		mock.ExpectQuery("SELECT id, comment_id, content, created_at FROM comment_history WHERE comment_id =").
+			WithArgs(commentId).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "comment_id", "content", "created_at"}).
+				AddRow(edit.Id, edit.CommentId, edit.Content, edit.CreatedAt))
+
+		result, err := repo.GetHistory(context.Background(), commentId)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.CommentEdit{edit}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SetLocked", func(t *testing.T) {
+		commentId := uuid.New()
+
+		mock.ExpectExec("UPDATE comments SET locked = \\$2 WHERE id = \\$1").
+			WithArgs(commentId, true).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := repo.SetLocked(context.Background(), commentId, true)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SetLocked not found", func(t *testing.T) {
+		commentId := uuid.New()
+
+		mock.ExpectExec("UPDATE comments SET locked").
+			WithArgs(commentId, false).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err := repo.SetLocked(context.Background(), commentId, false)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("IsThreadLocked", func(t *testing.T) {
+		commentId := uuid.New()
+
+		mock.ExpectQuery("SELECT COUNT(.+) FROM comments c JOIN comments a ON a.id = c.id OR a.id = ANY").
+			WithArgs(commentId).
+			WillReturnRows(pgxmock.NewRows([]string{"found", "locked"}).AddRow(true, true))
+
+		locked, err := repo.IsThreadLocked(context.Background(), commentId)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("IsThreadLocked not found", func(t *testing.T) {
+		commentId := uuid.New()
+
+		mock.ExpectQuery("SELECT COUNT(.+) FROM comments c JOIN comments a ON a.id = c.id OR a.id = ANY").
+			WithArgs(commentId).
+			WillReturnRows(pgxmock.NewRows([]string{"found", "locked"}).AddRow(false, false))
+
+		_, err := repo.IsThreadLocked(context.Background(), commentId)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetAncestors", func(t *testing.T) {
+		root := entity.Comment{
+			Id:        uuid.New(),
+			UserId:    uuid.New(),
+			PostId:    uuid.New(),
+			Content:   "Root",
+			CreatedAt: time.Now(),
+			Path:      []uuid.UUID{},
+		}
+		commentId := uuid.New()
+
+		mock.ExpectQuery("SELECT (.+) FROM comments c JOIN comments a ON a.id = ANY\\(c.path\\) WHERE c.id = \\$1 ORDER BY a.depth").
+			WithArgs(commentId).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at", "edited_at", "locked", "path", "depth"}).
+				AddRow(root.Id, root.UserId, root.PostId, root.ParentId, root.Content, root.CreatedAt, root.EditedAt, root.Locked, root.Path, root.Depth))
+
+		result, err := repo.GetAncestors(context.Background(), commentId)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Comment{root}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create parent on different post", func(t *testing.T) {
+		parentId := uuid.New()
+		comment := &entity.Comment{
+			Id:        uuid.New(),
+			UserId:    uuid.New(),
+			PostId:    uuid.New(),
+			ParentId:  &parentId,
+			Content:   "Reply",
+			CreatedAt: time.Now(),
+			Path:      []uuid.UUID{parentId},
+			Depth:     1,
+		}
+
+		mock.ExpectExec("INSERT INTO comments").
+			WithArgs(comment.Id, comment.UserId, comment.PostId, comment.ParentId,
+				comment.Content, comment.CreatedAt, comment.Path, comment.Depth).
+			WillReturnError(&pgconn.PgError{Code: "23503", ConstraintName: "comments_parent_same_post_fkey"})
+
+		err := repo.Create(context.Background(), comment)
+		assert.ErrorIs(t, err, repository.ErrParentOnDifferentPost)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetCrossPostReplies", func(t *testing.T) {
+		parentId := uuid.New()
+		reply := entity.Comment{
+			Id:        uuid.New(),
+			UserId:    uuid.New(),
+			PostId:    uuid.New(),
+			ParentId:  &parentId,
+			Content:   "Reply",
+			CreatedAt: time.Now(),
+			Path:      []uuid.UUID{parentId},
+			Depth:     1,
+		}
+
+		mock.ExpectQuery("SELECT (.+) FROM comments c JOIN comments p ON p.id = c.parent_id WHERE p.post_id <> c.post_id").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "post_id", "parent_id", "content", "created_at", "edited_at", "locked", "path", "depth"}).
+				AddRow(reply.Id, reply.UserId, reply.PostId, reply.ParentId, reply.Content, reply.CreatedAt, reply.EditedAt, reply.Locked, reply.Path, reply.Depth))
+
+		result, err := repo.GetCrossPostReplies(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Comment{reply}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
@@ -25,11 +25,11 @@ func (r *PostRepo) Create(ctx context.Context, post *entity.Post) error {
 	defer cancel()
 
 	query := `
-		INSERT INTO posts (id, user_id, title, content, is_commentable, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO posts (id, user_id, title, content, is_commentable, status, publish_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 	_, err := r.db.Exec(ctx, query,
-		post.Id, post.UserId, post.Title, post.Content, post.IsCommentable, post.CreatedAt)
+		post.Id, post.UserId, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, post.CreatedAt)
 	return err
 }
 
@@ -37,13 +37,22 @@ func (r *PostRepo) Update(ctx context.Context, post *entity.Post) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	// The previous text is copied into post_revisions in the same statement,
+	// so a revision exists if and only if the update went through.
 	query := `
+		WITH previous AS (
+			SELECT id, title, content FROM posts WHERE id = $1 FOR UPDATE
+		), revision AS (
+			INSERT INTO post_revisions (id, post_id, title, content, created_at)
+			SELECT $7, id, title, content, $8 FROM previous
+			WHERE title <> $2 OR content <> $3
+		)
 		UPDATE posts
-		SET title = $2, content = $3, is_commentable = $4
+		SET title = $2, content = $3, is_commentable = $4, status = $5, publish_at = $6
 		WHERE id = $1
 	`
 	result, err := r.db.Exec(ctx, query,
-		post.Id, post.Title, post.Content, post.IsCommentable)
+		post.Id, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, uuid.New(), time.Now())
 	if err != nil {
 		return err
 	}
@@ -61,38 +70,39 @@ func (r *PostRepo) GetOneById(ctx context.Context, id uuid.UUID) (*entity.Post,
 
 	var post entity.Post
 	query := `
-		SELECT id, user_id, title, content, is_commentable, created_at
+		SELECT id, user_id, title, content, is_commentable, status, publish_at, created_at
 		FROM posts
 		WHERE id = $1
 	`
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&post.Id, &post.UserId, &post.Title, &post.Content, &post.IsCommentable, &post.CreatedAt)
+		&post.Id, &post.UserId, &post.Title, &post.Content, &post.IsCommentable, &post.Status, &post.PublishAt, &post.CreatedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrNotFound
 	}
 	return &post, err
 }
 
-func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy repository.SortBy) ([]entity.Post, error) {
+func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy repository.SortBy, viewerId *uuid.UUID) ([]entity.Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var posts []entity.Post
 	builder := strings.Builder{}
-	builder.WriteString("SELECT id, user_id, title, content, is_commentable, created_at FROM posts")
+	builder.WriteString("SELECT id, user_id, title, content, is_commentable, status, publish_at, created_at FROM posts")
+	builder.WriteString(" WHERE status = 'PUBLISHED' OR user_id = $3")
 
 	switch sortBy {
 	case repository.SortByNewest:
-		builder.WriteString(" ORDER BY created_at DESC")
+		builder.WriteString(" ORDER BY COALESCE(publish_at, created_at) DESC")
 	case repository.SortByOldest:
-		builder.WriteString(" ORDER BY created_at ASC")
+		builder.WriteString(" ORDER BY COALESCE(publish_at, created_at) ASC")
 	default:
-		builder.WriteString(" ORDER BY created_at DESC")
+		builder.WriteString(" ORDER BY COALESCE(publish_at, created_at) DESC")
 	}
 
 	builder.WriteString(" LIMIT $1 OFFSET $2")
 
-	rows, err := r.db.Query(ctx, builder.String(), limit, offset)
+	rows, err := r.db.Query(ctx, builder.String(), limit, offset, viewerId)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +111,7 @@ func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy reposi
 	for rows.Next() {
 		var post entity.Post
 		if err := rows.Scan(
-			&post.Id, &post.UserId, &post.Title, &post.Content, &post.IsCommentable, &post.CreatedAt); err != nil {
+			&post.Id, &post.UserId, &post.Title, &post.Content, &post.IsCommentable, &post.Status, &post.PublishAt, &post.CreatedAt); err != nil {
 			return nil, err
 		}
 		posts = append(posts, post)
@@ -109,3 +119,127 @@ func (r *PostRepo) GetMany(ctx context.Context, limit, offset int, sortBy reposi
 
 	return posts, rows.Err()
 }
+
+func (r *PostRepo) GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID, includeUnpublished bool) ([]entity.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, title, content, is_commentable, status, publish_at, created_at
+		FROM posts
+		WHERE user_id = $1
+		  AND ($4 OR status = 'PUBLISHED')
+		  AND ($3::uuid IS NULL OR (created_at, id) < (SELECT created_at, id FROM posts WHERE id = $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, userId, limit, after, includeUnpublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []entity.Post
+	for rows.Next() {
+		var post entity.Post
+		if err := rows.Scan(
+			&post.Id, &post.UserId, &post.Title, &post.Content, &post.IsCommentable, &post.Status, &post.PublishAt, &post.CreatedAt); err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+func (r *PostRepo) CountByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM posts WHERE user_id = $1 AND status = 'PUBLISHED'`
+	if err := r.db.QueryRow(ctx, query, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PostRepo) PublishDue(ctx context.Context, now time.Time) ([]entity.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// The status check in UPDATE makes concurrent schedulers race for rows
+	// instead of publishing the same post twice.
+	query := `
+		UPDATE posts
+		SET status = 'PUBLISHED'
+		WHERE status = 'SCHEDULED' AND publish_at <= $1
+		RETURNING id, user_id, title, content, is_commentable, status, publish_at, created_at
+	`
+	rows, err := r.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]entity.Post, 0)
+	for rows.Next() {
+		var post entity.Post
+		if err := rows.Scan(
+			&post.Id, &post.UserId, &post.Title, &post.Content, &post.IsCommentable, &post.Status, &post.PublishAt, &post.CreatedAt); err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+func (r *PostRepo) GetRevisions(ctx context.Context, postId uuid.UUID, limit int, after *uuid.UUID) ([]entity.PostRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, post_id, title, content, created_at
+		FROM post_revisions
+		WHERE post_id = $1
+		  AND ($3::uuid IS NULL OR (created_at, id) < (SELECT created_at, id FROM post_revisions WHERE id = $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, postId, limit, after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]entity.PostRevision, 0)
+	for rows.Next() {
+		var revision entity.PostRevision
+		if err := rows.Scan(
+			&revision.Id, &revision.PostId, &revision.Title, &revision.Content, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (r *PostRepo) GetRevision(ctx context.Context, id uuid.UUID) (*entity.PostRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var revision entity.PostRevision
+	query := `
+		SELECT id, post_id, title, content, created_at
+		FROM post_revisions
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&revision.Id, &revision.PostId, &revision.Title, &revision.Content, &revision.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	return &revision, err
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,7 @@ func TestPostRepo(t *testing.T) {
 	defer mock.Close()
 
 	repo := postgres.NewPostRepo(mock)
+	now := time.Now()
 
 	t.Run("Create", func(t *testing.T) {
 		post := &entity.Post{
@@ -28,11 +30,13 @@ func TestPostRepo(t *testing.T) {
 			Title:         "Test Post",
 			Content:       "Test Content",
 			IsCommentable: true,
-			CreatedAt:     time.Now(),
+			Status:        entity.PostStatusPublished,
+			PublishAt:     &now,
+			CreatedAt:     now,
 		}
 
 		mock.ExpectExec("INSERT INTO posts").
-			WithArgs(post.Id, post.UserId, post.Title, post.Content, post.IsCommentable, post.CreatedAt).
+			WithArgs(post.Id, post.UserId, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, post.CreatedAt).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err := repo.Create(context.Background(), post)
@@ -46,10 +50,11 @@ func TestPostRepo(t *testing.T) {
 			Title:         "Updated Post",
 			Content:       "Updated Content",
 			IsCommentable: false,
+			Status:        entity.PostStatusDraft,
 		}
 
 		mock.ExpectExec("UPDATE posts").
-			WithArgs(post.Id, post.Title, post.Content, post.IsCommentable).
+			WithArgs(post.Id, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
 		err := repo.Update(context.Background(), post)
@@ -63,10 +68,11 @@ func TestPostRepo(t *testing.T) {
 			Title:         "Updated Post",
 			Content:       "Updated Content",
 			IsCommentable: false,
+			Status:        entity.PostStatusDraft,
 		}
 
 		mock.ExpectExec("UPDATE posts").
-			WithArgs(post.Id, post.Title, post.Content, post.IsCommentable).
+			WithArgs(post.Id, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
 
 		err := repo.Update(context.Background(), post)
@@ -81,14 +87,16 @@ func TestPostRepo(t *testing.T) {
 			Title:         "Test Post",
 			Content:       "Test Content",
 			IsCommentable: true,
-			CreatedAt:     time.Now(),
+			Status:        entity.PostStatusPublished,
+			PublishAt:     &now,
+			CreatedAt:     now,
 		}
 
-		mock.ExpectQuery("SELECT id, user_id, title, content, is_commentable, created_at FROM posts").
+		mock.ExpectQuery("SELECT id, user_id, title, content, is_commentable, status, publish_at, created_at FROM posts").
 			WithArgs(expectedPost.Id).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "title", "content", "is_commentable", "created_at"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "title", "content", "is_commentable", "status", "publish_at", "created_at"}).
 				AddRow(expectedPost.Id, expectedPost.UserId, expectedPost.Title, expectedPost.Content,
-					expectedPost.IsCommentable, expectedPost.CreatedAt))
+					expectedPost.IsCommentable, expectedPost.Status, expectedPost.PublishAt, expectedPost.CreatedAt))
 
 		post, err := repo.GetOneById(context.Background(), expectedPost.Id)
 		assert.NoError(t, err)
@@ -104,7 +112,9 @@ func TestPostRepo(t *testing.T) {
 				Title:         "Post 1",
 				Content:       "Content 1",
 				IsCommentable: true,
-				CreatedAt:     time.Now(),
+				Status:        entity.PostStatusPublished,
+				PublishAt:     &now,
+				CreatedAt:     now,
 			},
 			{
 				Id:            uuid.New(),
@@ -112,23 +122,121 @@ func TestPostRepo(t *testing.T) {
 				Title:         "Post 2",
 				Content:       "Content 2",
 				IsCommentable: false,
+				Status:        entity.PostStatusDraft,
 				CreatedAt:     time.Now().Add(-time.Hour),
 			},
 		}
 
-		mock.ExpectQuery("SELECT id, user_id, title, content, is_commentable, created_at FROM posts ORDER BY created_at DESC").
-			WithArgs(10, 0).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "title", "content", "is_commentable", "created_at"}).
+		viewerId := uuid.New()
+		mock.ExpectQuery(`SELECT (.+) FROM posts WHERE status = 'PUBLISHED' OR user_id = \$3 ORDER BY COALESCE\(publish_at, created_at\) DESC`).
+			WithArgs(10, 0, &viewerId).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "title", "content", "is_commentable", "status", "publish_at", "created_at"}).
 				AddRow(posts[0].Id, posts[0].UserId, posts[0].Title, posts[0].Content,
-					posts[0].IsCommentable, posts[0].CreatedAt).
+					posts[0].IsCommentable, posts[0].Status, posts[0].PublishAt, posts[0].CreatedAt).
 				AddRow(posts[1].Id, posts[1].UserId, posts[1].Title, posts[1].Content,
-					posts[1].IsCommentable, posts[1].CreatedAt))
+					posts[1].IsCommentable, posts[1].Status, posts[1].PublishAt, posts[1].CreatedAt))
 
-		result, err := repo.GetMany(context.Background(), 10, 0, repository.SortByNewest)
+		result, err := repo.GetMany(context.Background(), 10, 0, repository.SortByNewest, &viewerId)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 		assert.Equal(t, posts[0].Title, result[0].Title)
 		assert.Equal(t, posts[1].Title, result[1].Title)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("GetByUser", func(t *testing.T) {
+		userId := uuid.New()
+		after := uuid.New()
+		post := entity.Post{
+			Id:            uuid.New(),
+			UserId:        userId,
+			Title:         "Post 1",
+			Content:       "Content 1",
+			IsCommentable: true,
+			Status:        entity.PostStatusPublished,
+			PublishAt:     &now,
+			CreatedAt:     now,
+		}
+
+		mock.ExpectQuery("SELECT (.+) FROM posts WHERE user_id =").
+			WithArgs(userId, 10, &after, false).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "title", "content", "is_commentable", "status", "publish_at", "created_at"}).
+				AddRow(post.Id, post.UserId, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, post.CreatedAt))
+
+		result, err := repo.GetByUser(context.Background(), userId, 10, &after, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Post{post}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CountByUser", func(t *testing.T) {
+		userId := uuid.New()
+
+		mock.ExpectQuery("SELECT COUNT(.+) FROM posts WHERE user_id = (.+) AND status = 'PUBLISHED'").
+			WithArgs(userId).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+
+		count, err := repo.CountByUser(context.Background(), userId)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PublishDue", func(t *testing.T) {
+		publishAt := now.Add(-time.Minute)
+		post := entity.Post{
+			Id:            uuid.New(),
+			UserId:        uuid.New(),
+			Title:         "Scheduled",
+			Content:       "Content",
+			IsCommentable: true,
+			Status:        entity.PostStatusPublished,
+			PublishAt:     &publishAt,
+			CreatedAt:     now.Add(-time.Hour),
+		}
+
+		mock.ExpectQuery("UPDATE posts SET status = 'PUBLISHED' WHERE status = 'SCHEDULED' AND publish_at <= (.+) RETURNING").
+			WithArgs(now).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "title", "content", "is_commentable", "status", "publish_at", "created_at"}).
+				AddRow(post.Id, post.UserId, post.Title, post.Content, post.IsCommentable, post.Status, post.PublishAt, post.CreatedAt))
+
+		result, err := repo.PublishDue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Post{post}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetRevisions", func(t *testing.T) {
+		postId := uuid.New()
+		after := uuid.New()
+		revision := entity.PostRevision{
+			Id:        uuid.New(),
+			PostId:    postId,
+			Title:     "Old title",
+			Content:   "Old content",
+			CreatedAt: now,
+		}
+
+		mock.ExpectQuery("SELECT id, post_id, title, content, created_at FROM post_revisions WHERE post_id =").
+			WithArgs(postId, 10, &after).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "post_id", "title", "content", "created_at"}).
+				AddRow(revision.Id, revision.PostId, revision.Title, revision.Content, revision.CreatedAt))
+
+		result, err := repo.GetRevisions(context.Background(), postId, 10, &after)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.PostRevision{revision}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetRevision not found", func(t *testing.T) {
+		id := uuid.New()
+
+		mock.ExpectQuery("SELECT id, post_id, title, content, created_at FROM post_revisions WHERE id =").
+			WithArgs(id).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetRevision(context.Background(), id)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
@@ -2,14 +2,13 @@ package postgres
 
 import (
 	"app/internal/repository"
-
-	"github.com/jackc/pgx/v4/pgxpool"
 )
 
-func NewRepoHolder(pool *pgxpool.Pool) *repository.RepoHolder {
+func NewRepoHolder(db Database) *repository.RepoHolder {
 	return &repository.RepoHolder{
-		UserRepo:    NewUserRepo(pool),
-		PostRepo:    NewPostRepo(pool),
-		CommentRepo: NewCommentRepo(pool),
+		UserRepo:       NewUserRepo(db),
+		PostRepo:       NewPostRepo(db),
+		CommentRepo:    NewCommentRepo(db),
+		AttachmentRepo: NewAttachmentRepo(db),
 	}
 }
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// retryDatabase repeats statements that failed with a transient error,
+// doubling the delay after each attempt.
+type retryDatabase struct {
+	db       Database
+	attempts int
+	delay    time.Duration
+	logger   *slog.Logger
+}
+
+// WithRetry wraps db so that serialization failures, deadlocks and errors
+// pgconn knows happened before anything was sent are retried up to attempts
+// times in total.
+func WithRetry(db Database, attempts int, delay time.Duration, logger *slog.Logger) Database {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &retryDatabase{db: db, attempts: attempts, delay: delay, logger: logger}
+}
+
+func (r *retryDatabase) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := r.retry(ctx, func() error {
+		var err error
+		tag, err = r.db.Exec(ctx, sql, arguments...)
+		return err
+	})
+	return tag, err
+}
+
+func (r *retryDatabase) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := r.retry(ctx, func() error {
+		var err error
+		rows, err = r.db.Query(ctx, sql, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow defers the query errors to Scan, so the retry happens there.
+func (r *retryDatabase) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return &retryRow{db: r, ctx: ctx, sql: sql, args: args}
+}
+
+type retryRow struct {
+	db   *retryDatabase
+	ctx  context.Context
+	sql  string
+	args []interface{}
+}
+
+func (r *retryRow) Scan(dest ...interface{}) error {
+	return r.db.retry(r.ctx, func() error {
+		return r.db.db.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
+
+func (r *retryDatabase) retry(ctx context.Context, fn func() error) error {
+	delay := r.delay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt == r.attempts || !isTransient(err) {
+			return err
+		}
+
+		r.logger.WarnContext(ctx, "Transient database error, retrying",
+			"attempt", attempt, "attempts", r.attempts, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// isTransient leaves out broken connections in general: a write may have
+// been applied before the connection dropped, and repeating it is not safe.
+func isTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+	}
+
+	return pgconn.SafeToRetry(err)
+}
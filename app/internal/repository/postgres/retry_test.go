@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"app/internal/entity"
+	"context"
+	"io"
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	serializationErr := &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+
+	t.Run("exec retries serialization failure", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewUserRepo(WithRetry(mock, 3, time.Millisecond, testLogger))
+		user := &entity.User{Id: uuid.New(), Username: "testuser", Roles: []string{"user"}, CreatedAt: time.Now()}
+
+		mock.ExpectExec("INSERT INTO users").WillReturnError(serializationErr)
+		mock.ExpectExec("INSERT INTO users").
+			WithArgs(user.Id, user.Username, user.Roles, user.CreatedAt, user.DisplayName, user.Bio, user.AvatarKey).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.Create(context.Background(), user))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query row retries error safe to retry", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewUserRepo(WithRetry(mock, 3, time.Millisecond, testLogger))
+		userId := uuid.New()
+		now := time.Now()
+
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1").
+			WithArgs(userId).
+			WillReturnError(errNotSent)
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1").
+			WithArgs(userId).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "username", "roles", "created_at", "display_name", "bio", "avatar_key"}).
+				AddRow(userId, "testuser", []string{"user"}, now, nil, nil, nil))
+
+		user, err := repo.GetOneById(context.Background(), userId)
+		assert.NoError(t, err)
+		assert.Equal(t, "testuser", user.Username)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("does not retry writes on a broken connection", func(t *testing.T) {
+		for _, connErr := range []error{
+			syscall.ECONNRESET,
+			io.ErrUnexpectedEOF,
+			&pgconn.PgError{Code: "08006", Message: "connection failure"},
+		} {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+
+			repo := NewUserRepo(WithRetry(mock, 3, time.Millisecond, testLogger))
+			user := &entity.User{Id: uuid.New(), Username: "testuser", Roles: []string{"user"}, CreatedAt: time.Now()}
+
+			mock.ExpectExec("INSERT INTO users").WillReturnError(connErr)
+
+			assert.ErrorIs(t, repo.Create(context.Background(), user), connErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+			mock.Close()
+		}
+	})
+
+	t.Run("gives up after last attempt", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewCommentRepo(WithRetry(mock, 2, time.Millisecond, testLogger))
+
+		mock.ExpectQuery("SELECT (.+) FROM comments").WillReturnError(serializationErr)
+		mock.ExpectQuery("SELECT (.+) FROM comments").WillReturnError(serializationErr)
+
+		_, err = repo.GetCrossPostReplies(context.Background())
+		assert.ErrorIs(t, err, serializationErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewCommentRepo(WithRetry(mock, 3, time.Millisecond, testLogger))
+		syntaxErr := &pgconn.PgError{Code: "42601", Message: "syntax error"}
+
+		mock.ExpectQuery("SELECT (.+) FROM comments").WillReturnError(syntaxErr)
+
+		_, err = repo.GetCrossPostReplies(context.Background())
+		assert.ErrorIs(t, err, syntaxErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stops when context is done", func(t *testing.T) {
+		// pgxmock reports its own error for a canceled context, so a stub
+		// keeps failing with the transient error instead.
+		stub := &failingDatabase{err: serializationErr}
+		db := WithRetry(stub, 3, time.Hour, testLogger)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := db.Query(ctx, "SELECT id FROM comments")
+		assert.ErrorIs(t, err, serializationErr)
+		assert.Equal(t, 1, stub.calls)
+	})
+}
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// errNotSent stands for the errors pgconn marks as failed before the
+// statement reached the server.
+var errNotSent = notSentError{}
+
+type notSentError struct{}
+
+func (notSentError) Error() string     { return "connection closed before sending" }
+func (notSentError) SafeToRetry() bool { return true }
+
+type failingDatabase struct {
+	Database
+	err   error
+	calls int
+}
+
+func (f *failingDatabase) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	f.calls++
+	return nil, f.err
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"app/internal/tracing"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingDatabase opens a span for every statement.
+type tracingDatabase struct {
+	db Database
+}
+
+// WithTracing wraps db so that each statement is recorded as a client span
+// carrying its SQL text.
+func WithTracing(db Database) Database {
+	return &tracingDatabase{db: db}
+}
+
+func (t *tracingDatabase) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := startStatement(ctx, sql)
+	tag, err := t.db.Exec(ctx, sql, arguments...)
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", tag.RowsAffected()))
+	tracing.End(span, err)
+	return tag, err
+}
+
+// Query keeps the span open until the rows are closed.
+func (t *tracingDatabase) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startStatement(ctx, sql)
+	rows, err := t.db.Query(ctx, sql, args...)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	return &tracingRows{Rows: rows, span: span}, nil
+}
+
+// QueryRow keeps the span open until the row is scanned.
+func (t *tracingDatabase) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, span := startStatement(ctx, sql)
+	return &tracingRow{row: t.db.QueryRow(ctx, sql, args...), span: span}
+}
+
+type tracingRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+func (r *tracingRows) Close() {
+	r.Rows.Close()
+	tracing.End(r.span, r.Rows.Err())
+}
+
+type tracingRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r *tracingRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		tracing.End(r.span, nil)
+	} else {
+		tracing.End(r.span, err)
+	}
+	return err
+}
+
+func startStatement(ctx context.Context, sql string) (context.Context, trace.Span) {
+	sql = strings.TrimSpace(sql)
+	operation := "query"
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	return tracing.Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", sql),
+		),
+	)
+}
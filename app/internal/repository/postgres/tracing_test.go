@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"app/internal/entity"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	t.Run("span per statement", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewUserRepo(WithTracing(mock))
+		user := &entity.User{Id: uuid.New(), Username: "testuser", Roles: []string{"user"}, CreatedAt: time.Now()}
+
+		mock.ExpectExec("INSERT INTO users").
+			WithArgs(user.Id, user.Username, user.Roles, user.CreatedAt, user.DisplayName, user.Bio, user.AvatarKey).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1").
+			WithArgs(user.Id).
+			WillReturnError(pgx.ErrNoRows)
+
+		require.NoError(t, repo.Create(context.Background(), user))
+		_, err = repo.GetOneById(context.Background(), user.Id)
+		require.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "postgres INSERT", spans[0].Name())
+		assert.Equal(t, "postgres SELECT", spans[1].Name())
+		// A missing row is an expected outcome, not a failed statement.
+		assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	})
+
+	t.Run("failed statement", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		db := WithTracing(mock)
+		mock.ExpectQuery("SELECT id FROM comments").WillReturnError(errors.New("connection refused"))
+
+		_, err = db.Query(context.Background(), "SELECT id FROM comments")
+		require.Error(t, err)
+
+		spans := recorder.Ended()
+		last := spans[len(spans)-1]
+		assert.Equal(t, "postgres SELECT", last.Name())
+		assert.Equal(t, codes.Error, last.Status().Code)
+	})
+}
@@ -23,18 +23,44 @@ func (r *UserRepo) Create(ctx context.Context, user *entity.User) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO users (id, username, roles) VALUES ($1, $2, $3)`
-	_, err := r.db.Exec(ctx, query, user.Id, user.Username, user.Roles)
+	query := `
+		INSERT INTO users (id, username, roles, created_at, display_name, bio, avatar_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(ctx, query,
+		user.Id, user.Username, user.Roles, user.CreatedAt, user.DisplayName, user.Bio, user.AvatarKey)
 	return err
 }
 
+func (r *UserRepo) Update(ctx context.Context, user *entity.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE users
+		SET display_name = $2, bio = $3, avatar_key = $4
+		WHERE id = $1
+	`
+	result, err := r.db.Exec(ctx, query, user.Id, user.DisplayName, user.Bio, user.AvatarKey)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepo) GetOneById(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var user entity.User
-	query := `SELECT id, username, roles FROM users WHERE id = $1`
-	err := r.db.QueryRow(ctx, query, id).Scan(&user.Id, &user.Username, &user.Roles)
+	query := `SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&user.Id, &user.Username, &user.Roles, &user.CreatedAt, &user.DisplayName, &user.Bio, &user.AvatarKey)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
@@ -55,7 +81,7 @@ func (r *UserRepo) GetManyByIds(ctx context.Context, ids []uuid.UUID) (map[uuid.
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	query := `SELECT id, username, roles FROM users WHERE id = ANY($1)`
+	query := `SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id = ANY($1)`
 	rows, err := r.db.Query(ctx, query, ids)
 	if err != nil {
 		return nil, err
@@ -65,7 +91,8 @@ func (r *UserRepo) GetManyByIds(ctx context.Context, ids []uuid.UUID) (map[uuid.
 	users := make(map[uuid.UUID]entity.User)
 	for rows.Next() {
 		var user entity.User
-		if err := rows.Scan(&user.Id, &user.Username, &user.Roles); err != nil {
+		if err := rows.Scan(
+			&user.Id, &user.Username, &user.Roles, &user.CreatedAt, &user.DisplayName, &user.Bio, &user.AvatarKey); err != nil {
 			return nil, err
 		}
 		users[user.Id] = user
@@ -79,8 +106,9 @@ func (r *UserRepo) GetOneByUsername(ctx context.Context, username string) (*enti
 	defer cancel()
 
 	var user entity.User
-	query := `SELECT id, username, roles FROM users WHERE username = $1`
-	err := r.db.QueryRow(ctx, query, username).Scan(&user.Id, &user.Username, &user.Roles)
+	query := `SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE username = $1`
+	err := r.db.QueryRow(ctx, query, username).Scan(
+		&user.Id, &user.Username, &user.Roles, &user.CreatedAt, &user.DisplayName, &user.Bio, &user.AvatarKey)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
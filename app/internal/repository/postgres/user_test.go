@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
@@ -23,13 +24,14 @@ func TestUserRepo_Create(t *testing.T) {
 		repo := NewUserRepo(mock)
 
 		user := &entity.User{
-			Id:       uuid.New(),
-			Username: "testuser",
-			Roles:    []string{"user"},
+			Id:        uuid.New(),
+			Username:  "testuser",
+			Roles:     []string{"user"},
+			CreatedAt: time.Now(),
 		}
 
 		mock.ExpectExec("INSERT INTO users").
-			WithArgs(user.Id, user.Username, user.Roles).
+			WithArgs(user.Id, user.Username, user.Roles, user.CreatedAt, user.DisplayName, user.Bio, user.AvatarKey).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err = repo.Create(context.Background(), user)
@@ -38,6 +40,49 @@ func TestUserRepo_Create(t *testing.T) {
 	})
 }
 
+func TestUserRepo_Update(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewUserRepo(mock)
+
+		user := &entity.User{
+			Id:          uuid.New(),
+			DisplayName: "Test User",
+			Bio:         "About me",
+			AvatarKey:   "avatars/test.png",
+		}
+
+		mock.ExpectExec("UPDATE users").
+			WithArgs(user.Id, user.DisplayName, user.Bio, user.AvatarKey).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err = repo.Update(context.Background(), user)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewUserRepo(mock)
+
+		user := &entity.User{Id: uuid.New()}
+
+		mock.ExpectExec("UPDATE users").
+			WithArgs(user.Id, user.DisplayName, user.Bio, user.AvatarKey).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err = repo.Update(context.Background(), user)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestUserRepo_GetOneById(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mock, err := pgxmock.NewPool()
@@ -47,15 +92,17 @@ func TestUserRepo_GetOneById(t *testing.T) {
 		repo := NewUserRepo(mock)
 
 		expectedUser := &entity.User{
-			Id:       uuid.New(),
-			Username: "testuser",
-			Roles:    []string{"user"},
+			Id:        uuid.New(),
+			Username:  "testuser",
+			Roles:     []string{"user"},
+			CreatedAt: time.Now(),
 		}
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE id =").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id =").
 			WithArgs(expectedUser.Id).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "username", "roles"}).
-				AddRow(expectedUser.Id, expectedUser.Username, expectedUser.Roles))
+			WillReturnRows(pgxmock.NewRows([]string{"id", "username", "roles", "created_at", "display_name", "bio", "avatar_key"}).
+				AddRow(expectedUser.Id, expectedUser.Username, expectedUser.Roles, expectedUser.CreatedAt,
+					expectedUser.DisplayName, expectedUser.Bio, expectedUser.AvatarKey))
 
 		user, err := repo.GetOneById(context.Background(), expectedUser.Id)
 		assert.NoError(t, err)
@@ -72,7 +119,7 @@ func TestUserRepo_GetOneById(t *testing.T) {
 
 		id := uuid.New()
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE id =").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id =").
 			WithArgs(id).
 			WillReturnError(pgx.ErrNoRows)
 
@@ -92,7 +139,7 @@ func TestUserRepo_GetOneById(t *testing.T) {
 		id := uuid.New()
 		expectedErr := errors.New("database error")
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE id =").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id =").
 			WithArgs(id).
 			WillReturnError(expectedErr)
 
@@ -112,15 +159,17 @@ func TestUserRepo_GetOneByUsername(t *testing.T) {
 		repo := NewUserRepo(mock)
 
 		expectedUser := &entity.User{
-			Id:       uuid.New(),
-			Username: "testuser",
-			Roles:    []string{"user"},
+			Id:        uuid.New(),
+			Username:  "testuser",
+			Roles:     []string{"user"},
+			CreatedAt: time.Now(),
 		}
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE username =").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE username =").
 			WithArgs(expectedUser.Username).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "username", "roles"}).
-				AddRow(expectedUser.Id, expectedUser.Username, expectedUser.Roles))
+			WillReturnRows(pgxmock.NewRows([]string{"id", "username", "roles", "created_at", "display_name", "bio", "avatar_key"}).
+				AddRow(expectedUser.Id, expectedUser.Username, expectedUser.Roles, expectedUser.CreatedAt,
+					expectedUser.DisplayName, expectedUser.Bio, expectedUser.AvatarKey))
 
 		user, err := repo.GetOneByUsername(context.Background(), expectedUser.Username)
 		assert.NoError(t, err)
@@ -137,7 +186,7 @@ func TestUserRepo_GetOneByUsername(t *testing.T) {
 
 		username := "nonexistent"
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE username =").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE username =").
 			WithArgs(username).
 			WillReturnError(pgx.ErrNoRows)
 
@@ -157,7 +206,7 @@ func TestUserRepo_GetOneByUsername(t *testing.T) {
 		username := "testuser"
 		expectedErr := errors.New("database error")
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE username =").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE username =").
 			WithArgs(username).
 			WillReturnError(expectedErr)
 
@@ -177,8 +226,8 @@ func TestUserRepo_GetManyByIds(t *testing.T) {
 		repo := NewUserRepo(mock)
 
 		users := []entity.User{
-			{Id: uuid.New(), Username: "user1", Roles: []string{"user"}},
-			{Id: uuid.New(), Username: "user2", Roles: []string{"admin"}},
+			{Id: uuid.New(), Username: "user1", Roles: []string{"user"}, CreatedAt: time.Now()},
+			{Id: uuid.New(), Username: "user2", Roles: []string{"admin"}, CreatedAt: time.Now()},
 		}
 
 		ids := make([]uuid.UUID, len(users))
@@ -186,12 +235,12 @@ func TestUserRepo_GetManyByIds(t *testing.T) {
 			ids[i] = u.Id
 		}
 
-		rows := pgxmock.NewRows([]string{"id", "username", "roles"})
+		rows := pgxmock.NewRows([]string{"id", "username", "roles", "created_at", "display_name", "bio", "avatar_key"})
 		for _, u := range users {
-			rows.AddRow(u.Id, u.Username, u.Roles)
+			rows.AddRow(u.Id, u.Username, u.Roles, u.CreatedAt, u.DisplayName, u.Bio, u.AvatarKey)
 		}
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE id = ANY").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id = ANY").
 			WithArgs(ids).
 			WillReturnRows(rows)
 
@@ -226,7 +275,7 @@ func TestUserRepo_GetManyByIds(t *testing.T) {
 		ids := []uuid.UUID{uuid.New()}
 		expectedErr := errors.New("database error")
 
-		mock.ExpectQuery("SELECT id, username, roles FROM users WHERE id = ANY").
+		mock.ExpectQuery("SELECT id, username, roles, created_at, display_name, bio, avatar_key FROM users WHERE id = ANY").
 			WithArgs(ids).
 			WillReturnError(expectedErr)
 
@@ -3,6 +3,7 @@ package repository
 import (
 	"app/internal/entity"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -19,6 +20,7 @@ const (
 
 type UserRepo interface {
 	Create(ctx context.Context, user *entity.User) error
+	Update(ctx context.Context, user *entity.User) error
 	GetOneById(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	GetManyByIds(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]entity.User, error)
 	GetOneByUsername(ctx context.Context, username string) (*entity.User, error)
@@ -28,19 +30,51 @@ type PostRepo interface {
 	Create(ctx context.Context, post *entity.Post) error
 	Update(ctx context.Context, post *entity.Post) error
 	GetOneById(ctx context.Context, id uuid.UUID) (*entity.Post, error)
-	GetMany(ctx context.Context, limit, offset int, sortBy SortBy) ([]entity.Post, error)
+	// GetMany lists published posts plus unpublished ones of the viewer, if any.
+	GetMany(ctx context.Context, limit, offset int, sortBy SortBy, viewerId *uuid.UUID) ([]entity.Post, error)
+	GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID, includeUnpublished bool) ([]entity.Post, error)
+	// CountByUser counts published posts only.
+	CountByUser(ctx context.Context, userId uuid.UUID) (int, error)
+	// PublishDue publishes scheduled posts whose time has come and returns them.
+	// Each post is returned by exactly one call, even with concurrent callers.
+	PublishDue(ctx context.Context, now time.Time) ([]entity.Post, error)
+
+	// Update also stores the replaced title and content as a revision
+	// whenever either of them changes; revisions are listed newest first.
+	GetRevisions(ctx context.Context, postId uuid.UUID, limit int, after *uuid.UUID) ([]entity.PostRevision, error)
+	GetRevision(ctx context.Context, id uuid.UUID) (*entity.PostRevision, error)
 }
 
 type CommentRepo interface {
 	Create(ctx context.Context, comment *entity.Comment) error
 	GetOneById(ctx context.Context, commentId uuid.UUID) (*entity.Comment, error)
+	// Update appends the replaced content to the comment's history when it
+	// changes. History entries are never modified or removed.
+	Update(ctx context.Context, comment *entity.Comment) error
+	// GetHistory returns previous contents of the comment, oldest first.
+	GetHistory(ctx context.Context, commentId uuid.UUID) ([]entity.CommentEdit, error)
+	SetLocked(ctx context.Context, commentId uuid.UUID, locked bool) error
+	// IsThreadLocked reports whether the comment or any of its ancestors is locked.
+	IsThreadLocked(ctx context.Context, commentId uuid.UUID) (bool, error)
+	// GetAncestors returns the comments above the given one, root first.
+	GetAncestors(ctx context.Context, commentId uuid.UUID) ([]entity.Comment, error)
+	// GetCrossPostReplies returns replies whose parent belongs to a different post.
+	GetCrossPostReplies(ctx context.Context) ([]entity.Comment, error)
 
 	GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]entity.Comment, error)
 	GetCommentReplies(ctx context.Context, parentId uuid.UUID, limit, offset int) ([]entity.Comment, error)
+	GetByUser(ctx context.Context, userId uuid.UUID, limit int, after *uuid.UUID) ([]entity.Comment, error)
+	CountByUser(ctx context.Context, userId uuid.UUID) (int, error)
+}
+
+type AttachmentRepo interface {
+	Create(ctx context.Context, attachment *entity.Attachment) error
+	GetByOwner(ctx context.Context, ownerType entity.AttachmentOwner, ownerId uuid.UUID) ([]entity.Attachment, error)
 }
 
 type RepoHolder struct {
 	UserRepo
 	PostRepo
 	CommentRepo
+	AttachmentRepo
 }
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"app/graph/model"
+	"app/internal/blobstore"
+	"app/internal/entity"
+	"app/internal/repository"
+	"app/internal/thumbnail"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxAttachmentsPerItem is how many attachments a post or comment can have.
+const MaxAttachmentsPerItem int = 10
+
+const (
+	maxAttachmentSize int64 = 10 << 20
+	thumbnailSize     int   = 320
+)
+
+var attachmentExtensions = map[string]string{
+	"image/png":                 ".png",
+	"image/jpeg":                ".jpg",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"application/pdf":           ".pdf",
+	"application/zip":           ".zip",
+	"text/plain; charset=utf-8": ".txt",
+}
+
+var thumbnailTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+}
+
+type AttachmentService struct {
+	RepoHolder *repository.RepoHolder
+	BlobStore  blobstore.BlobStore
+	Logger     *slog.Logger
+}
+
+func (s *AttachmentService) AddPostAttachment(ctx context.Context, postId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error) {
+	post, err := s.RepoHolder.PostRepo.GetOneById(ctx, postId)
+	if err != nil {
+		return nil, ErrPostNotFound
+	}
+	if post.UserId != userId {
+		return nil, ErrNoPermissionForAttachment
+	}
+
+	return s.addAttachment(ctx, entity.AttachmentOwnerPost, postId, userId, file)
+}
+
+func (s *AttachmentService) AddCommentAttachment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error) {
+	comment, err := s.RepoHolder.CommentRepo.GetOneById(ctx, commentId)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+	if comment.UserId != userId {
+		return nil, ErrNoPermissionForAttachment
+	}
+
+	return s.addAttachment(ctx, entity.AttachmentOwnerComment, commentId, userId, file)
+}
+
+func (s *AttachmentService) GetByPost(ctx context.Context, postId uuid.UUID) ([]*model.Attachment, error) {
+	return s.getByOwner(ctx, entity.AttachmentOwnerPost, postId)
+}
+
+func (s *AttachmentService) GetByComment(ctx context.Context, commentId uuid.UUID) ([]*model.Attachment, error) {
+	return s.getByOwner(ctx, entity.AttachmentOwnerComment, commentId)
+}
+
+func (s *AttachmentService) getByOwner(ctx context.Context, ownerType entity.AttachmentOwner, ownerId uuid.UUID) ([]*model.Attachment, error) {
+	attachmentEntities, err := s.RepoHolder.AttachmentRepo.GetByOwner(ctx, ownerType, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	attachments := make([]*model.Attachment, 0, len(attachmentEntities))
+	for i := range attachmentEntities {
+		attachments = append(attachments, newAttachmentModel(&attachmentEntities[i]))
+	}
+
+	return attachments, nil
+}
+
+func (s *AttachmentService) addAttachment(ctx context.Context, ownerType entity.AttachmentOwner, ownerId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error) {
+	if file.Size > maxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	existing, err := s.RepoHolder.AttachmentRepo.GetByOwner(ctx, ownerType, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	if len(existing) >= MaxAttachmentsPerItem {
+		return nil, ErrTooManyAttachments
+	}
+
+	// The declared size comes from the client, so the limit is enforced
+	// on the bytes actually read as well.
+	data, err := io.ReadAll(io.LimitReader(file.Content, maxAttachmentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if int64(len(data)) > maxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedAttachmentType
+	}
+
+	attachment, err := entity.NewAttachment(ownerType, ownerId, userId, sanitizeFilename(file.Filename), contentType, int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("Validation error: %w", err)
+	}
+
+	keyPrefix := fmt.Sprintf("attachments/%ss/%s/%s", ownerType, ownerId, attachment.Id)
+	attachment.BlobKey = keyPrefix + ext
+	if err := s.BlobStore.Put(ctx, attachment.BlobKey, bytes.NewReader(data), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	if thumbnailTypes[contentType] {
+		attachment.ThumbnailKey = s.storeThumbnail(ctx, keyPrefix+"_thumb.png", data)
+	}
+
+	if err := s.RepoHolder.AttachmentRepo.Create(ctx, attachment); err != nil {
+		s.deleteBlob(ctx, attachment.BlobKey)
+		if attachment.ThumbnailKey != "" {
+			s.deleteBlob(ctx, attachment.ThumbnailKey)
+		}
+		return nil, err
+	}
+
+	return newAttachmentModel(attachment), nil
+}
+
+// storeThumbnail returns the key of the stored thumbnail or an empty string
+// if one could not be made. A missing thumbnail does not fail the upload.
+func (s *AttachmentService) storeThumbnail(ctx context.Context, key string, data []byte) string {
+	thumb, err := thumbnail.Generate(data, thumbnailSize)
+	if err != nil {
+		s.Logger.WarnContext(ctx, "Failed to generate thumbnail", "key", key, "error", err)
+		return ""
+	}
+	if err := s.BlobStore.Put(ctx, key, bytes.NewReader(thumb), "image/png"); err != nil {
+		s.Logger.WarnContext(ctx, "Failed to store thumbnail", "key", key, "error", err)
+		return ""
+	}
+	return key
+}
+
+func (s *AttachmentService) deleteBlob(ctx context.Context, key string) {
+	if err := s.BlobStore.Delete(ctx, key); err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+		s.Logger.WarnContext(ctx, "Failed to delete blob", "key", key, "error", err)
+	}
+}
+
+// sanitizeFilename drops any client-side directories from the uploaded name.
+func sanitizeFilename(filename string) string {
+	name := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+func newAttachmentModel(attachment *entity.Attachment) *model.Attachment {
+	return &model.Attachment{
+		ID:           attachment.Id.String(),
+		Filename:     attachment.Filename,
+		ContentType:  attachment.ContentType,
+		Size:         int32(attachment.Size),
+		BlobKey:      attachment.BlobKey,
+		ThumbnailKey: attachment.ThumbnailKey,
+		CreatedAt:    attachment.CreatedAt,
+	}
+}
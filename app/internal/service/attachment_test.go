@@ -0,0 +1,255 @@
+package service_test
+
+import (
+	mock_blobstore "app/internal/blobstore/mocks"
+	"app/internal/entity"
+	"app/internal/repository"
+	mock_repository "app/internal/repository/mocks"
+	"app/internal/service"
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentService_AddPostAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockAttachmentRepo := mock_repository.NewMockAttachmentRepo(ctrl)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, AttachmentRepo: mockAttachmentRepo}
+	attachmentService := &service.AttachmentService{RepoHolder: repoHolder, BlobStore: mockBlobStore, Logger: testLogger}
+
+	authorID := uuid.New()
+	postID := uuid.New()
+	post := &entity.Post{Id: postID, UserId: authorID}
+
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 640, 480))))
+
+	t.Run("image with thumbnail", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().
+			GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).
+			Return([]entity.Attachment{}, nil)
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), "image/png").Return(nil).Times(2)
+		mockAttachmentRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, attachment *entity.Attachment) {
+				assert.Equal(t, entity.AttachmentOwnerPost, attachment.OwnerType)
+				assert.Equal(t, postID, attachment.OwnerId)
+				assert.Equal(t, "photo.png", attachment.Filename)
+			}).
+			Return(nil)
+
+		file := &service.FileUpload{
+			Filename: `C:\Users\me\photo.png`,
+			Size:     int64(img.Len()),
+			Content:  bytes.NewReader(img.Bytes()),
+		}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		require.NoError(t, err)
+		assert.Equal(t, "photo.png", result.Filename)
+		assert.Equal(t, "image/png", result.ContentType)
+		assert.Equal(t, int32(img.Len()), result.Size)
+		assert.True(t, strings.HasPrefix(result.BlobKey, "attachments/posts/"+postID.String()+"/"))
+		assert.True(t, strings.HasSuffix(result.ThumbnailKey, "_thumb.png"))
+	})
+
+	t.Run("failing to store thumbnail is only logged", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).Return(nil, nil)
+		mockBlobStore.EXPECT().
+			Put(gomock.Any(), gomock.Any(), gomock.Any(), "image/png").
+			DoAndReturn(func(_ context.Context, key string, _ io.Reader, _ string) error {
+				if strings.HasSuffix(key, "_thumb.png") {
+					return errors.New("storage unavailable")
+				}
+				return nil
+			}).
+			Times(2)
+		mockAttachmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		file := &service.FileUpload{Filename: "photo.png", Size: int64(img.Len()), Content: bytes.NewReader(img.Bytes())}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.ThumbnailKey)
+	})
+
+	t.Run("text file without thumbnail", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).Return(nil, nil)
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), "text/plain; charset=utf-8").Return(nil)
+		mockAttachmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		file := &service.FileUpload{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(result.BlobKey, ".txt"))
+		assert.Empty(t, result.ThumbnailKey)
+	})
+
+	t.Run("not an author", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+
+		file := &service.FileUpload{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, uuid.New(), file)
+
+		assert.ErrorIs(t, err, service.ErrNoPermissionForAttachment)
+		assert.Nil(t, result)
+	})
+
+	t.Run("post not found", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(nil, repository.ErrNotFound)
+
+		file := &service.FileUpload{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		assert.ErrorIs(t, err, service.ErrPostNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("declared size too large", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+
+		file := &service.FileUpload{Filename: "big.txt", Size: 11 << 20, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		assert.ErrorIs(t, err, service.ErrAttachmentTooLarge)
+		assert.Nil(t, result)
+	})
+
+	t.Run("actual size too large", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).Return(nil, nil)
+
+		file := &service.FileUpload{Filename: "big.txt", Size: 1, Content: strings.NewReader(strings.Repeat("a", 10<<20+1))}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		assert.ErrorIs(t, err, service.ErrAttachmentTooLarge)
+		assert.Nil(t, result)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).Return(nil, nil)
+
+		content := "<html><body>hi</body></html>"
+		file := &service.FileUpload{Filename: "page.png", Size: int64(len(content)), Content: strings.NewReader(content)}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		assert.ErrorIs(t, err, service.ErrUnsupportedAttachmentType)
+		assert.Nil(t, result)
+	})
+
+	t.Run("too many attachments", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().
+			GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).
+			Return(make([]entity.Attachment, 10), nil)
+
+		file := &service.FileUpload{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		assert.ErrorIs(t, err, service.ErrTooManyAttachments)
+		assert.Nil(t, result)
+	})
+
+	t.Run("repository error removes stored blob", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(post, nil)
+		mockAttachmentRepo.EXPECT().GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).Return(nil, nil)
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockAttachmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+		mockBlobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+
+		file := &service.FileUpload{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddPostAttachment(context.Background(), postID, authorID, file)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestAttachmentService_AddCommentAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	mockAttachmentRepo := mock_repository.NewMockAttachmentRepo(ctrl)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(ctrl)
+	repoHolder := &repository.RepoHolder{CommentRepo: mockCommentRepo, AttachmentRepo: mockAttachmentRepo}
+	attachmentService := &service.AttachmentService{RepoHolder: repoHolder, BlobStore: mockBlobStore, Logger: testLogger}
+
+	authorID := uuid.New()
+	commentID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(&entity.Comment{Id: commentID, UserId: authorID}, nil)
+		mockAttachmentRepo.EXPECT().GetByOwner(gomock.Any(), entity.AttachmentOwnerComment, commentID).Return(nil, nil)
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), "application/pdf").Return(nil)
+		mockAttachmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		content := "%PDF-1.4 document"
+		file := &service.FileUpload{Filename: "doc.pdf", Size: int64(len(content)), Content: strings.NewReader(content)}
+		result, err := attachmentService.AddCommentAttachment(context.Background(), commentID, authorID, file)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.BlobKey, "attachments/comments/"+commentID.String()+"/"))
+		assert.True(t, strings.HasSuffix(result.BlobKey, ".pdf"))
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(nil, repository.ErrNotFound)
+
+		file := &service.FileUpload{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")}
+		result, err := attachmentService.AddCommentAttachment(context.Background(), commentID, authorID, file)
+
+		assert.ErrorIs(t, err, service.ErrCommentNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestAttachmentService_GetByPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttachmentRepo := mock_repository.NewMockAttachmentRepo(ctrl)
+	repoHolder := &repository.RepoHolder{AttachmentRepo: mockAttachmentRepo}
+	attachmentService := &service.AttachmentService{RepoHolder: repoHolder}
+
+	postID := uuid.New()
+	attachment := entity.Attachment{
+		Id:          uuid.New(),
+		OwnerType:   entity.AttachmentOwnerPost,
+		OwnerId:     postID,
+		Filename:    "photo.png",
+		ContentType: "image/png",
+		Size:        128,
+		BlobKey:     "attachments/posts/photo.png",
+	}
+
+	mockAttachmentRepo.EXPECT().
+		GetByOwner(gomock.Any(), entity.AttachmentOwnerPost, postID).
+		Return([]entity.Attachment{attachment}, nil)
+
+	result, err := attachmentService.GetByPost(context.Background(), postID)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, attachment.Id.String(), result[0].ID)
+	assert.Equal(t, attachment.BlobKey, result[0].BlobKey)
+}
@@ -5,7 +5,9 @@ import (
 	"app/internal/entity"
 	"app/internal/repository"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,6 +16,8 @@ const maxSymbolsLength int = 2000
 
 type CommentService struct {
 	RepoHolder *repository.RepoHolder
+	// MaxDepth limits how deep replies can be nested, zero means no limit.
+	MaxDepth int
 }
 
 func (s *CommentService) CreateComment(ctx context.Context, userId uuid.UUID, postId uuid.UUID, parentId *uuid.UUID, content string) (*model.Comment, error) {
@@ -23,7 +27,7 @@ func (s *CommentService) CreateComment(ctx context.Context, userId uuid.UUID, po
 	}
 	post, err := s.RepoHolder.PostRepo.GetOneById(ctx, postId)
 
-	if err != nil {
+	if err != nil || !post.IsPublished() {
 		return nil, ErrPostNotFound
 	}
 
@@ -36,11 +40,28 @@ func (s *CommentService) CreateComment(ctx context.Context, userId uuid.UUID, po
 		return nil, err
 	}
 
+	var parent *entity.Comment
 	if parentId != nil {
-		_, err := s.RepoHolder.CommentRepo.GetOneById(ctx, *parentId)
+		parent, err = s.RepoHolder.CommentRepo.GetOneById(ctx, *parentId)
 		if err != nil {
 			return nil, ErrParentCommentNotFound
 		}
+
+		if parent.PostId != postId {
+			return nil, ErrParentOnDifferentPost
+		}
+
+		if s.MaxDepth > 0 && parent.Depth+1 > s.MaxDepth {
+			return nil, ErrMaxDepthExceeded
+		}
+
+		locked, err := s.RepoHolder.CommentRepo.IsThreadLocked(ctx, *parentId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check thread lock: %w", err)
+		}
+		if locked {
+			return nil, ErrThreadLocked
+		}
 	}
 
 	newComment, err := entity.NewComment(userId, postId, parentId, content)
@@ -49,26 +70,18 @@ func (s *CommentService) CreateComment(ctx context.Context, userId uuid.UUID, po
 		return nil, err
 	}
 
-	if err := s.RepoHolder.CommentRepo.Create(ctx, newComment); err != nil {
-		return nil, err
+	if parent != nil {
+		newComment.ReplyTo(parent)
 	}
 
-	var ParentIdString *string
-	if parentId != nil {
-		parentStr := parentId.String()
-		ParentIdString = &parentStr
+	if err := s.RepoHolder.CommentRepo.Create(ctx, newComment); err != nil {
+		if errors.Is(err, repository.ErrParentOnDifferentPost) {
+			return nil, ErrParentOnDifferentPost
+		}
+		return nil, err
 	}
 
-	return &model.Comment{
-		ID:       newComment.Id.String(),
-		ParentID: ParentIdString,
-		User: &model.User{
-			ID:       userId.String(),
-			Username: user.Username,
-		},
-		Content:   content,
-		CreatedAt: newComment.CreatedAt,
-	}, nil
+	return newCommentModel(newComment, user), nil
 }
 
 func (s *CommentService) GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*model.Comment, error) {
@@ -93,21 +106,12 @@ func (s *CommentService) GetByPost(ctx context.Context, postId uuid.UUID, limit,
 	}
 
 	comments := make([]*model.Comment, 0, len(commentEntities))
-	for _, commentEntity := range commentEntities {
-		userEntity, exists := usersMap[commentEntity.UserId]
+	for i := range commentEntities {
+		userEntity, exists := usersMap[commentEntities[i].UserId]
 		if !exists {
-			return nil, fmt.Errorf("user not found for comment %s", commentEntity.Id)
+			return nil, fmt.Errorf("user not found for comment %s", commentEntities[i].Id)
 		}
-		comments = append(comments, &model.Comment{
-			ID:        commentEntity.Id.String(),
-			Content:   commentEntity.Content,
-			CreatedAt: commentEntity.CreatedAt,
-			ParentID:  nil,
-			User: &model.User{
-				ID:       userEntity.Id.String(),
-				Username: userEntity.Username,
-			},
-		})
+		comments = append(comments, newCommentModel(&commentEntities[i], &userEntity))
 	}
 
 	return comments, nil
@@ -139,27 +143,194 @@ func (s *CommentService) GetCommentReplies(ctx context.Context, parentId uuid.UU
 	}
 
 	replies := make([]*model.Comment, 0, len(replyEntities))
-	for _, replyEntity := range replyEntities {
-		userEntity, exists := usersMap[replyEntity.UserId]
+	for i := range replyEntities {
+		userEntity, exists := usersMap[replyEntities[i].UserId]
 		if !exists {
-			return nil, fmt.Errorf("user not found for reply %s", replyEntity.Id)
+			return nil, fmt.Errorf("user not found for reply %s", replyEntities[i].Id)
 		}
-		var replyEntityParent *string
-		if replyEntity.ParentId != nil {
-			parentStr := replyEntity.ParentId.String()
-			replyEntityParent = &parentStr
+		replies = append(replies, newCommentModel(&replyEntities[i], &userEntity))
+	}
+
+	return replies, nil
+}
+
+func (s *CommentService) GetAncestors(ctx context.Context, commentId uuid.UUID) ([]*model.Comment, error) {
+	ancestorEntities, err := s.RepoHolder.CommentRepo.GetAncestors(ctx, commentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ancestors: %w", err)
+	}
+
+	if len(ancestorEntities) == 0 {
+		return []*model.Comment{}, nil
+	}
+
+	userIds := make([]uuid.UUID, 0, len(ancestorEntities))
+	for _, ancestor := range ancestorEntities {
+		userIds = append(userIds, ancestor.UserId)
+	}
+
+	users, err := s.RepoHolder.UserRepo.GetManyByIds(ctx, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users for ancestors: %w", err)
+	}
+
+	usersMap := make(map[uuid.UUID]entity.User, len(users))
+	for _, user := range users {
+		usersMap[user.Id] = user
+	}
+
+	ancestors := make([]*model.Comment, 0, len(ancestorEntities))
+	for i := range ancestorEntities {
+		userEntity, exists := usersMap[ancestorEntities[i].UserId]
+		if !exists {
+			return nil, fmt.Errorf("user not found for ancestor %s", ancestorEntities[i].Id)
 		}
-		replies = append(replies, &model.Comment{
-			ID:        replyEntity.Id.String(),
-			Content:   replyEntity.Content,
-			ParentID:  replyEntityParent,
-			CreatedAt: replyEntity.CreatedAt,
-			User: &model.User{
-				ID:       userEntity.Id.String(),
-				Username: userEntity.Username,
-			},
+		ancestors = append(ancestors, newCommentModel(&ancestorEntities[i], &userEntity))
+	}
+
+	return ancestors, nil
+}
+
+func (s *CommentService) GetByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID) ([]*model.Comment, error) {
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	commentEntities, err := s.RepoHolder.CommentRepo.GetByUser(ctx, userId, first, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user comments: %w", err)
+	}
+
+	comments := make([]*model.Comment, 0, len(commentEntities))
+	for i := range commentEntities {
+		comments = append(comments, newCommentModel(&commentEntities[i], user))
+	}
+
+	return comments, nil
+}
+
+func (s *CommentService) EditComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, content string) (*model.Comment, error) {
+	if len([]rune(content)) > maxSymbolsLength {
+		return nil, ErrTooManySymbols
+	}
+
+	comment, err := s.RepoHolder.CommentRepo.GetOneById(ctx, commentId)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+	if comment.UserId != userId {
+		return nil, ErrNoPermissionForComment
+	}
+
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	changed, err := comment.Edit(content, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("Validation error: %w", err)
+	}
+
+	// Saving the same content again would only add a history entry
+	if changed {
+		if err := s.RepoHolder.CommentRepo.Update(ctx, comment); err != nil {
+			return nil, err
+		}
+	}
+
+	return newCommentModel(comment, user), nil
+}
+
+// GetHistory returns previous contents of the comment. Only the author and
+// moderators may see what a comment used to say.
+func (s *CommentService) GetHistory(ctx context.Context, commentId uuid.UUID, viewerId uuid.UUID) ([]*model.CommentEdit, error) {
+	comment, err := s.RepoHolder.CommentRepo.GetOneById(ctx, commentId)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+
+	if comment.UserId != viewerId {
+		viewer, err := s.RepoHolder.UserRepo.GetOneById(ctx, viewerId)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		if !viewer.IsModerator() {
+			return nil, ErrNoPermissionForHistory
+		}
+	}
+
+	historyEntities, err := s.RepoHolder.CommentRepo.GetHistory(ctx, commentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment history: %w", err)
+	}
+
+	history := make([]*model.CommentEdit, 0, len(historyEntities))
+	for _, edit := range historyEntities {
+		history = append(history, &model.CommentEdit{
+			ID:        edit.Id.String(),
+			Content:   edit.Content,
+			CreatedAt: edit.CreatedAt,
 		})
 	}
 
-	return replies, nil
+	return history, nil
+}
+
+// SetThreadLocked locks or unlocks the sub-thread starting at the comment.
+// Moderators can lock any thread, post authors only threads under their posts.
+func (s *CommentService) SetThreadLocked(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, locked bool) (*model.Comment, error) {
+	comment, err := s.RepoHolder.CommentRepo.GetOneById(ctx, commentId)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.IsModerator() {
+		post, err := s.RepoHolder.PostRepo.GetOneById(ctx, comment.PostId)
+		if err != nil {
+			return nil, ErrPostNotFound
+		}
+		if post.UserId != userId {
+			return nil, ErrNoPermissionForLock
+		}
+	}
+
+	if err := s.RepoHolder.CommentRepo.SetLocked(ctx, commentId, locked); err != nil {
+		return nil, err
+	}
+	comment.Locked = locked
+
+	author := user
+	if comment.UserId != userId {
+		if author, err = s.RepoHolder.UserRepo.GetOneById(ctx, comment.UserId); err != nil {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	return newCommentModel(comment, author), nil
+}
+
+func newCommentModel(comment *entity.Comment, user *entity.User) *model.Comment {
+	var parentId *string
+	if comment.ParentId != nil {
+		parentStr := comment.ParentId.String()
+		parentId = &parentStr
+	}
+
+	return &model.Comment{
+		ID:        comment.Id.String(),
+		ParentID:  parentId,
+		User:      newUserModel(user),
+		Content:   comment.Content,
+		Edited:    comment.IsEdited(),
+		Locked:    comment.Locked,
+		Depth:     int32(comment.Depth),
+		CreatedAt: comment.CreatedAt,
+	}
 }
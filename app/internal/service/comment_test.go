@@ -45,6 +45,7 @@ func TestCommentService_CreateComment(t *testing.T) {
 			Return(&entity.Post{
 				Id:            postID,
 				IsCommentable: true,
+				Status:        entity.PostStatusPublished,
 			}, nil)
 
 		mockUserRepo.EXPECT().
@@ -54,19 +55,29 @@ func TestCommentService_CreateComment(t *testing.T) {
 				Username: "testuser",
 			}, nil)
 
+		rootID := uuid.New()
 		mockCommentRepo.EXPECT().
 			GetOneById(gomock.Any(), parentID).
-			Return(&entity.Comment{}, nil)
+			Return(&entity.Comment{Id: parentID, PostId: postID, Path: []uuid.UUID{rootID}, Depth: 1}, nil)
+
+		mockCommentRepo.EXPECT().
+			IsThreadLocked(gomock.Any(), parentID).
+			Return(false, nil)
 
 		mockCommentRepo.EXPECT().
 			Create(gomock.Any(), gomock.Any()).
-			Return(nil)
+			DoAndReturn(func(_ context.Context, comment *entity.Comment) error {
+				assert.Equal(t, []uuid.UUID{rootID, parentID}, comment.Path)
+				assert.Equal(t, 2, comment.Depth)
+				return nil
+			})
 
 		result, err := service.CreateComment(context.Background(), userID, postID, &parentID, content)
 
 		assert.NoError(t, err)
 		assert.Equal(t, content, result.Content)
 		assert.Equal(t, parentID.String(), *result.ParentID)
+		assert.Equal(t, int32(2), result.Depth)
 		assert.Equal(t, userID.String(), result.User.ID)
 	})
 
@@ -78,6 +89,7 @@ func TestCommentService_CreateComment(t *testing.T) {
 			Return(&entity.Post{
 				Id:            postID,
 				IsCommentable: true,
+				Status:        entity.PostStatusPublished,
 			}, nil)
 
 		mockUserRepo.EXPECT().
@@ -111,6 +123,85 @@ func TestCommentService_CreateComment(t *testing.T) {
 		assert.Nil(t, result)
 	})
 
+	t.Run("locked thread", func(t *testing.T) {
+		cService, mockPostRepo, mockUserRepo, mockCommentRepo := setup()
+
+		mockPostRepo.EXPECT().
+			GetOneById(gomock.Any(), postID).
+			Return(&entity.Post{
+				Id:            postID,
+				IsCommentable: true,
+				Status:        entity.PostStatusPublished,
+			}, nil)
+
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID}, nil)
+
+		mockCommentRepo.EXPECT().
+			GetOneById(gomock.Any(), parentID).
+			Return(&entity.Comment{Id: parentID, PostId: postID}, nil)
+
+		mockCommentRepo.EXPECT().
+			IsThreadLocked(gomock.Any(), parentID).
+			Return(true, nil)
+
+		result, err := cService.CreateComment(context.Background(), userID, postID, &parentID, content)
+
+		assert.ErrorIs(t, err, service.ErrThreadLocked)
+		assert.Nil(t, result)
+	})
+
+	t.Run("parent on different post", func(t *testing.T) {
+		cService, mockPostRepo, mockUserRepo, mockCommentRepo := setup()
+
+		mockPostRepo.EXPECT().
+			GetOneById(gomock.Any(), postID).
+			Return(&entity.Post{
+				Id:            postID,
+				IsCommentable: true,
+				Status:        entity.PostStatusPublished,
+			}, nil)
+
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID}, nil)
+
+		mockCommentRepo.EXPECT().
+			GetOneById(gomock.Any(), parentID).
+			Return(&entity.Comment{Id: parentID, PostId: uuid.New()}, nil)
+
+		result, err := cService.CreateComment(context.Background(), userID, postID, &parentID, content)
+
+		assert.ErrorIs(t, err, service.ErrParentOnDifferentPost)
+		assert.Nil(t, result)
+	})
+
+	t.Run("max depth exceeded", func(t *testing.T) {
+		cService, mockPostRepo, mockUserRepo, mockCommentRepo := setup()
+		cService.MaxDepth = 2
+
+		mockPostRepo.EXPECT().
+			GetOneById(gomock.Any(), postID).
+			Return(&entity.Post{
+				Id:            postID,
+				IsCommentable: true,
+				Status:        entity.PostStatusPublished,
+			}, nil)
+
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID}, nil)
+
+		mockCommentRepo.EXPECT().
+			GetOneById(gomock.Any(), parentID).
+			Return(&entity.Comment{Id: parentID, PostId: postID, Path: []uuid.UUID{uuid.New(), uuid.New()}, Depth: 2}, nil)
+
+		result, err := cService.CreateComment(context.Background(), userID, postID, &parentID, content)
+
+		assert.ErrorIs(t, err, service.ErrMaxDepthExceeded)
+		assert.Nil(t, result)
+	})
 }
 
 func TestCommentService_GetByPost(t *testing.T) {
@@ -222,3 +313,258 @@ func TestCommentService_GetCommentReplies(t *testing.T) {
 		assert.Equal(t, "Reply 1", result[0].Content)
 	})
 }
+
+func TestCommentService_GetAncestors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	cService := &service.CommentService{RepoHolder: &repository.RepoHolder{
+		CommentRepo: mockCommentRepo,
+		UserRepo:    mockUserRepo,
+	}}
+
+	commentID := uuid.New()
+	userID := uuid.New()
+	rootID := uuid.New()
+	parentID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockCommentRepo.EXPECT().
+			GetAncestors(gomock.Any(), commentID).
+			Return([]entity.Comment{
+				{Id: rootID, UserId: userID, Content: "Root", Path: []uuid.UUID{}},
+				{Id: parentID, UserId: userID, ParentId: &rootID, Content: "Parent", Path: []uuid.UUID{rootID}, Depth: 1},
+			}, nil)
+
+		mockUserRepo.EXPECT().
+			GetManyByIds(gomock.Any(), []uuid.UUID{userID, userID}).
+			Return(map[uuid.UUID]entity.User{userID: {Id: userID, Username: "testuser"}}, nil)
+
+		result, err := cService.GetAncestors(context.Background(), commentID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "Root", result[0].Content)
+		assert.Equal(t, int32(1), result[1].Depth)
+	})
+
+	t.Run("root comment", func(t *testing.T) {
+		mockCommentRepo.EXPECT().
+			GetAncestors(gomock.Any(), rootID).
+			Return([]entity.Comment{}, nil)
+
+		result, err := cService.GetAncestors(context.Background(), rootID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestCommentService_GetByUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{CommentRepo: mockCommentRepo, UserRepo: mockUserRepo}
+	commentService := &service.CommentService{RepoHolder: repoHolder}
+
+	userID := uuid.New()
+	parentID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser"}, nil)
+
+		mockCommentRepo.EXPECT().
+			GetByUser(gomock.Any(), userID, 10, nil).
+			Return([]entity.Comment{
+				{Id: uuid.New(), UserId: userID, ParentId: &parentID, Content: "Reply"},
+			}, nil)
+
+		result, err := commentService.GetByUser(context.Background(), userID, 10, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, parentID.String(), *result[0].ParentID)
+		assert.Equal(t, "testuser", result[0].User.Username)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(nil, repository.ErrNotFound)
+
+		result, err := commentService.GetByUser(context.Background(), userID, 10, nil)
+
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestCommentService_EditComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{CommentRepo: mockCommentRepo, UserRepo: mockUserRepo}
+	commentService := &service.CommentService{RepoHolder: repoHolder}
+
+	userID := uuid.New()
+	commentID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		comment := &entity.Comment{Id: commentID, UserId: userID, PostId: uuid.New(), Content: "Original"}
+
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment, nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), userID).Return(&entity.User{Id: userID}, nil)
+		mockCommentRepo.EXPECT().Update(gomock.Any(), comment).Return(nil)
+
+		result, err := commentService.EditComment(context.Background(), commentID, userID, "Edited")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Edited", result.Content)
+		assert.True(t, result.Edited)
+	})
+
+	t.Run("unchanged content is not saved", func(t *testing.T) {
+		comment := &entity.Comment{Id: commentID, UserId: userID, PostId: uuid.New(), Content: "Original"}
+
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment, nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), userID).Return(&entity.User{Id: userID}, nil)
+
+		result, err := commentService.EditComment(context.Background(), commentID, userID, "Original")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Original", result.Content)
+		assert.False(t, result.Edited)
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		comment := &entity.Comment{Id: commentID, UserId: uuid.New(), PostId: uuid.New(), Content: "Original"}
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment, nil)
+
+		_, err := commentService.EditComment(context.Background(), commentID, userID, "Edited")
+
+		assert.ErrorIs(t, err, service.ErrNoPermissionForComment)
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(nil, repository.ErrNotFound)
+
+		_, err := commentService.EditComment(context.Background(), commentID, userID, "Edited")
+
+		assert.ErrorIs(t, err, service.ErrCommentNotFound)
+	})
+}
+
+func TestCommentService_GetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{CommentRepo: mockCommentRepo, UserRepo: mockUserRepo}
+	commentService := &service.CommentService{RepoHolder: repoHolder}
+
+	authorID := uuid.New()
+	commentID := uuid.New()
+	comment := &entity.Comment{Id: commentID, UserId: authorID, Content: "Edited"}
+	history := []entity.CommentEdit{{Id: uuid.New(), CommentId: commentID, Content: "Original", CreatedAt: time.Now()}}
+
+	t.Run("author", func(t *testing.T) {
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment, nil)
+		mockCommentRepo.EXPECT().GetHistory(gomock.Any(), commentID).Return(history, nil)
+
+		result, err := commentService.GetHistory(context.Background(), commentID, authorID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "Original", result[0].Content)
+	})
+
+	t.Run("moderator", func(t *testing.T) {
+		moderatorID := uuid.New()
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment, nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), moderatorID).
+			Return(&entity.User{Id: moderatorID, Roles: []string{entity.RoleUser, entity.RoleModerator}}, nil)
+		mockCommentRepo.EXPECT().GetHistory(gomock.Any(), commentID).Return(history, nil)
+
+		result, err := commentService.GetHistory(context.Background(), commentID, moderatorID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		viewerID := uuid.New()
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment, nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), viewerID).
+			Return(&entity.User{Id: viewerID, Roles: []string{entity.RoleUser}}, nil)
+
+		_, err := commentService.GetHistory(context.Background(), commentID, viewerID)
+
+		assert.ErrorIs(t, err, service.ErrNoPermissionForHistory)
+	})
+}
+
+func TestCommentService_SetThreadLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	repoHolder := &repository.RepoHolder{CommentRepo: mockCommentRepo, UserRepo: mockUserRepo, PostRepo: mockPostRepo}
+	commentService := &service.CommentService{RepoHolder: repoHolder}
+
+	postAuthorID := uuid.New()
+	commentAuthorID := uuid.New()
+	commentID := uuid.New()
+	postID := uuid.New()
+	comment := func() *entity.Comment {
+		return &entity.Comment{Id: commentID, UserId: commentAuthorID, PostId: postID, Content: "Thread"}
+	}
+
+	t.Run("post author", func(t *testing.T) {
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment(), nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), postAuthorID).Return(&entity.User{Id: postAuthorID}, nil)
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(&entity.Post{Id: postID, UserId: postAuthorID}, nil)
+		mockCommentRepo.EXPECT().SetLocked(gomock.Any(), commentID, true).Return(nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), commentAuthorID).Return(&entity.User{Id: commentAuthorID}, nil)
+
+		result, err := commentService.SetThreadLocked(context.Background(), commentID, postAuthorID, true)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Locked)
+		assert.Equal(t, commentAuthorID.String(), result.User.ID)
+	})
+
+	t.Run("moderator", func(t *testing.T) {
+		moderatorID := uuid.New()
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment(), nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), moderatorID).
+			Return(&entity.User{Id: moderatorID, Roles: []string{entity.RoleModerator}}, nil)
+		mockCommentRepo.EXPECT().SetLocked(gomock.Any(), commentID, false).Return(nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), commentAuthorID).Return(&entity.User{Id: commentAuthorID}, nil)
+
+		result, err := commentService.SetThreadLocked(context.Background(), commentID, moderatorID, false)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Locked)
+	})
+
+	t.Run("comment author without rights", func(t *testing.T) {
+		mockCommentRepo.EXPECT().GetOneById(gomock.Any(), commentID).Return(comment(), nil)
+		mockUserRepo.EXPECT().GetOneById(gomock.Any(), commentAuthorID).Return(&entity.User{Id: commentAuthorID}, nil)
+		mockPostRepo.EXPECT().GetOneById(gomock.Any(), postID).Return(&entity.Post{Id: postID, UserId: postAuthorID}, nil)
+
+		_, err := commentService.SetThreadLocked(context.Background(), commentID, commentAuthorID, true)
+
+		assert.ErrorIs(t, err, service.ErrNoPermissionForLock)
+	})
+}
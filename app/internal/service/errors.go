@@ -9,7 +9,24 @@ var (
 	ErrUserNotFound          = errors.New("User not found")
 	ErrPostNotFound          = errors.New("Post not found")
 	ErrNoPermissionForToggle = errors.New("Only creator can toggle comments")
+	ErrNoPermissionForPost   = errors.New("Only author can manage the post")
+	ErrRevisionNotFound      = errors.New("Revision not found")
+	ErrRevisionsMismatch     = errors.New("Revisions belong to different posts")
 	ErrPostIsNotCommentable  = errors.New("Post is not commentable")
 	ErrParentCommentNotFound = errors.New("Parent comment not found")
+	ErrParentOnDifferentPost = errors.New("Parent comment belongs to another post")
 	ErrTooManySymbols        = errors.New("Too many symbols")
+	ErrAvatarTooLarge        = errors.New("Avatar is too large")
+	ErrUnsupportedAvatarType = errors.New("Unsupported avatar type")
+
+	ErrCommentNotFound           = errors.New("Comment not found")
+	ErrNoPermissionForComment    = errors.New("Only author can edit the comment")
+	ErrNoPermissionForHistory    = errors.New("Only author or moderators can view comment history")
+	ErrNoPermissionForLock       = errors.New("Only moderators or post author can lock threads")
+	ErrThreadLocked              = errors.New("Thread is locked")
+	ErrMaxDepthExceeded          = errors.New("Maximum reply depth exceeded")
+	ErrNoPermissionForAttachment = errors.New("Only author can add attachments")
+	ErrAttachmentTooLarge        = errors.New("Attachment is too large")
+	ErrUnsupportedAttachmentType = errors.New("Unsupported attachment type")
+	ErrTooManyAttachments        = errors.New("Too many attachments")
 )
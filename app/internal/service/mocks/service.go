@@ -6,8 +6,10 @@ package mock_service
 
 import (
 	model "app/graph/model"
+	service "app/internal/service"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -66,6 +68,51 @@ func (mr *MockUserMockRecorder) GetUser(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUser)(nil).GetUser), ctx, id)
 }
 
+// GetUserByUsername mocks base method.
+func (m *MockUser) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", ctx, username)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockUserMockRecorder) GetUserByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockUser)(nil).GetUserByUsername), ctx, username)
+}
+
+// GetUserStats mocks base method.
+func (m *MockUser) GetUserStats(ctx context.Context, id uuid.UUID) (*model.UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStats", ctx, id)
+	ret0, _ := ret[0].(*model.UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStats indicates an expected call of GetUserStats.
+func (mr *MockUserMockRecorder) GetUserStats(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStats", reflect.TypeOf((*MockUser)(nil).GetUserStats), ctx, id)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUser) UpdateProfile(ctx context.Context, userId uuid.UUID, displayName, bio *string, avatar *service.FileUpload) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, userId, displayName, bio, avatar)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserMockRecorder) UpdateProfile(ctx, userId, displayName, bio, avatar interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUser)(nil).UpdateProfile), ctx, userId, displayName, bio, avatar)
+}
+
 // MockPost is a mock of Post interface.
 type MockPost struct {
 	ctrl     *gomock.Controller
@@ -104,34 +151,169 @@ func (mr *MockPostMockRecorder) CreatePost(ctx, userId, title, content, isCommen
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockPost)(nil).CreatePost), ctx, userId, title, content, isCommentable)
 }
 
+// DiffRevisions mocks base method.
+func (m *MockPost) DiffRevisions(ctx context.Context, fromId uuid.UUID, toId, viewerId *uuid.UUID) (*model.PostDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, fromId, toId, viewerId)
+	ret0, _ := ret[0].(*model.PostDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockPostMockRecorder) DiffRevisions(ctx, fromId, toId, viewerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockPost)(nil).DiffRevisions), ctx, fromId, toId, viewerId)
+}
+
+// EditPost mocks base method.
+func (m *MockPost) EditPost(ctx context.Context, postId, userId uuid.UUID, title, content string) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditPost", ctx, postId, userId, title, content)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditPost indicates an expected call of EditPost.
+func (mr *MockPostMockRecorder) EditPost(ctx, postId, userId, title, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditPost", reflect.TypeOf((*MockPost)(nil).EditPost), ctx, postId, userId, title, content)
+}
+
 // GetPostById mocks base method.
-func (m *MockPost) GetPostById(ctx context.Context, id uuid.UUID) (*model.Post, error) {
+func (m *MockPost) GetPostById(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPostById", ctx, id)
+	ret := m.ctrl.Call(m, "GetPostById", ctx, id, viewerId)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPostById indicates an expected call of GetPostById.
-func (mr *MockPostMockRecorder) GetPostById(ctx, id interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) GetPostById(ctx, id, viewerId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostById", reflect.TypeOf((*MockPost)(nil).GetPostById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostById", reflect.TypeOf((*MockPost)(nil).GetPostById), ctx, id, viewerId)
 }
 
 // GetPosts mocks base method.
-func (m *MockPost) GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy) ([]*model.Post, error) {
+func (m *MockPost) GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy, viewerId *uuid.UUID) ([]*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx, limit, offset, sortBy)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, limit, offset, sortBy, viewerId)
 	ret0, _ := ret[0].([]*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockPostMockRecorder) GetPosts(ctx, limit, offset, sortBy interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) GetPosts(ctx, limit, offset, sortBy, viewerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPost)(nil).GetPosts), ctx, limit, offset, sortBy, viewerId)
+}
+
+// GetPostsByUser mocks base method.
+func (m *MockPost) GetPostsByUser(ctx context.Context, userId uuid.UUID, first int, after, viewerId *uuid.UUID) ([]*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostsByUser", ctx, userId, first, after, viewerId)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostsByUser indicates an expected call of GetPostsByUser.
+func (mr *MockPostMockRecorder) GetPostsByUser(ctx, userId, first, after, viewerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByUser", reflect.TypeOf((*MockPost)(nil).GetPostsByUser), ctx, userId, first, after, viewerId)
+}
+
+// GetRevision mocks base method.
+func (m *MockPost) GetRevision(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id, viewerId)
+	ret0, _ := ret[0].(*model.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockPostMockRecorder) GetRevision(ctx, id, viewerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockPost)(nil).GetRevision), ctx, id, viewerId)
+}
+
+// GetRevisions mocks base method.
+func (m *MockPost) GetRevisions(ctx context.Context, postId uuid.UUID, first int, after *uuid.UUID) ([]*model.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, postId, first, after)
+	ret0, _ := ret[0].([]*model.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockPostMockRecorder) GetRevisions(ctx, postId, first, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockPost)(nil).GetRevisions), ctx, postId, first, after)
+}
+
+// PublishDuePosts mocks base method.
+func (m *MockPost) PublishDuePosts(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDuePosts", ctx, now)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDuePosts indicates an expected call of PublishDuePosts.
+func (mr *MockPostMockRecorder) PublishDuePosts(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDuePosts", reflect.TypeOf((*MockPost)(nil).PublishDuePosts), ctx, now)
+}
+
+// PublishPost mocks base method.
+func (m *MockPost) PublishPost(ctx context.Context, postId, userId uuid.UUID) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishPost", ctx, postId, userId)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishPost indicates an expected call of PublishPost.
+func (mr *MockPostMockRecorder) PublishPost(ctx, postId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishPost", reflect.TypeOf((*MockPost)(nil).PublishPost), ctx, postId, userId)
+}
+
+// SaveDraft mocks base method.
+func (m *MockPost) SaveDraft(ctx context.Context, userId uuid.UUID, postId *uuid.UUID, title, content string, isCommentable bool) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDraft", ctx, userId, postId, title, content, isCommentable)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDraft indicates an expected call of SaveDraft.
+func (mr *MockPostMockRecorder) SaveDraft(ctx, userId, postId, title, content, isCommentable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDraft", reflect.TypeOf((*MockPost)(nil).SaveDraft), ctx, userId, postId, title, content, isCommentable)
+}
+
+// SchedulePost mocks base method.
+func (m *MockPost) SchedulePost(ctx context.Context, postId, userId uuid.UUID, publishAt time.Time) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePost", ctx, postId, userId, publishAt)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePost indicates an expected call of SchedulePost.
+func (mr *MockPostMockRecorder) SchedulePost(ctx, postId, userId, publishAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPost)(nil).GetPosts), ctx, limit, offset, sortBy)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePost", reflect.TypeOf((*MockPost)(nil).SchedulePost), ctx, postId, userId, publishAt)
 }
 
 // TogglePostComments mocks base method.
@@ -186,6 +368,36 @@ func (mr *MockCommentMockRecorder) CreateComment(ctx, userId, postId, parentId,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockComment)(nil).CreateComment), ctx, userId, postId, parentId, content)
 }
 
+// EditComment mocks base method.
+func (m *MockComment) EditComment(ctx context.Context, commentId, userId uuid.UUID, content string) (*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditComment", ctx, commentId, userId, content)
+	ret0, _ := ret[0].(*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditComment indicates an expected call of EditComment.
+func (mr *MockCommentMockRecorder) EditComment(ctx, commentId, userId, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditComment", reflect.TypeOf((*MockComment)(nil).EditComment), ctx, commentId, userId, content)
+}
+
+// GetAncestors mocks base method.
+func (m *MockComment) GetAncestors(ctx context.Context, commentId uuid.UUID) ([]*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAncestors", ctx, commentId)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAncestors indicates an expected call of GetAncestors.
+func (mr *MockCommentMockRecorder) GetAncestors(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAncestors", reflect.TypeOf((*MockComment)(nil).GetAncestors), ctx, commentId)
+}
+
 // GetByPost mocks base method.
 func (m *MockComment) GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*model.Comment, error) {
 	m.ctrl.T.Helper()
@@ -201,6 +413,21 @@ func (mr *MockCommentMockRecorder) GetByPost(ctx, postId, limit, offset interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPost", reflect.TypeOf((*MockComment)(nil).GetByPost), ctx, postId, limit, offset)
 }
 
+// GetByUser mocks base method.
+func (m *MockComment) GetByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID) ([]*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userId, first, after)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockCommentMockRecorder) GetByUser(ctx, userId, first, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockComment)(nil).GetByUser), ctx, userId, first, after)
+}
+
 // GetCommentReplies mocks base method.
 func (m *MockComment) GetCommentReplies(ctx context.Context, parentId uuid.UUID, limit, offset int) ([]*model.Comment, error) {
 	m.ctrl.T.Helper()
@@ -215,3 +442,116 @@ func (mr *MockCommentMockRecorder) GetCommentReplies(ctx, parentId, limit, offse
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentReplies", reflect.TypeOf((*MockComment)(nil).GetCommentReplies), ctx, parentId, limit, offset)
 }
+
+// GetHistory mocks base method.
+func (m *MockComment) GetHistory(ctx context.Context, commentId, viewerId uuid.UUID) ([]*model.CommentEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, commentId, viewerId)
+	ret0, _ := ret[0].([]*model.CommentEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockCommentMockRecorder) GetHistory(ctx, commentId, viewerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockComment)(nil).GetHistory), ctx, commentId, viewerId)
+}
+
+// SetThreadLocked mocks base method.
+func (m *MockComment) SetThreadLocked(ctx context.Context, commentId, userId uuid.UUID, locked bool) (*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetThreadLocked", ctx, commentId, userId, locked)
+	ret0, _ := ret[0].(*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetThreadLocked indicates an expected call of SetThreadLocked.
+func (mr *MockCommentMockRecorder) SetThreadLocked(ctx, commentId, userId, locked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetThreadLocked", reflect.TypeOf((*MockComment)(nil).SetThreadLocked), ctx, commentId, userId, locked)
+}
+
+// MockAttachment is a mock of Attachment interface.
+type MockAttachment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentMockRecorder
+}
+
+// MockAttachmentMockRecorder is the mock recorder for MockAttachment.
+type MockAttachmentMockRecorder struct {
+	mock *MockAttachment
+}
+
+// NewMockAttachment creates a new mock instance.
+func NewMockAttachment(ctrl *gomock.Controller) *MockAttachment {
+	mock := &MockAttachment{ctrl: ctrl}
+	mock.recorder = &MockAttachmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachment) EXPECT() *MockAttachmentMockRecorder {
+	return m.recorder
+}
+
+// AddCommentAttachment mocks base method.
+func (m *MockAttachment) AddCommentAttachment(ctx context.Context, commentId, userId uuid.UUID, file *service.FileUpload) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCommentAttachment", ctx, commentId, userId, file)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCommentAttachment indicates an expected call of AddCommentAttachment.
+func (mr *MockAttachmentMockRecorder) AddCommentAttachment(ctx, commentId, userId, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommentAttachment", reflect.TypeOf((*MockAttachment)(nil).AddCommentAttachment), ctx, commentId, userId, file)
+}
+
+// AddPostAttachment mocks base method.
+func (m *MockAttachment) AddPostAttachment(ctx context.Context, postId, userId uuid.UUID, file *service.FileUpload) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPostAttachment", ctx, postId, userId, file)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPostAttachment indicates an expected call of AddPostAttachment.
+func (mr *MockAttachmentMockRecorder) AddPostAttachment(ctx, postId, userId, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPostAttachment", reflect.TypeOf((*MockAttachment)(nil).AddPostAttachment), ctx, postId, userId, file)
+}
+
+// GetByComment mocks base method.
+func (m *MockAttachment) GetByComment(ctx context.Context, commentId uuid.UUID) ([]*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByComment", ctx, commentId)
+	ret0, _ := ret[0].([]*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByComment indicates an expected call of GetByComment.
+func (mr *MockAttachmentMockRecorder) GetByComment(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByComment", reflect.TypeOf((*MockAttachment)(nil).GetByComment), ctx, commentId)
+}
+
+// GetByPost mocks base method.
+func (m *MockAttachment) GetByPost(ctx context.Context, postId uuid.UUID) ([]*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPost", ctx, postId)
+	ret0, _ := ret[0].([]*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPost indicates an expected call of GetByPost.
+func (mr *MockAttachmentMockRecorder) GetByPost(ctx, postId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPost", reflect.TypeOf((*MockAttachment)(nil).GetByPost), ctx, postId)
+}
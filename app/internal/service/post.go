@@ -2,9 +2,13 @@ package service
 
 import (
 	"app/graph/model"
+	"app/internal/diff"
 	"app/internal/entity"
 	"app/internal/repository"
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,32 +17,26 @@ type PostService struct {
 	RepoHolder *repository.RepoHolder
 }
 
-func (s *PostService) GetPostById(ctx context.Context, id uuid.UUID) (*model.Post, error) {
+func (s *PostService) GetPostById(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.Post, error) {
 
 	newPost, err := s.RepoHolder.PostRepo.GetOneById(ctx, id)
 	if err != nil {
 		return nil, ErrPostNotFound
 	}
 
+	if !newPost.IsPublished() && !isAuthor(newPost, viewerId) {
+		return nil, ErrPostNotFound
+	}
+
 	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, newPost.UserId)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 
-	return &model.Post{
-		ID:            newPost.Id.String(),
-		Title:         newPost.Title,
-		Content:       newPost.Content,
-		IsCommentable: newPost.IsCommentable,
-		CreatedAt:     newPost.CreatedAt,
-		User: &model.User{
-			ID:       user.Id.String(),
-			Username: user.Username,
-		},
-	}, nil
+	return newPostModel(newPost, user), nil
 }
 
-func (s *PostService) GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy) ([]*model.Post, error) {
+func (s *PostService) GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy, viewerId *uuid.UUID) ([]*model.Post, error) {
 	rSortBy := repository.SortByNewest
 	if sortBy != nil {
 		rSortBy = repository.SortBy(*sortBy)
@@ -49,76 +47,125 @@ func (s *PostService) GetPosts(ctx context.Context, limit, offset int, sortBy *m
 		limit,
 		offset,
 		rSortBy,
+		viewerId,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	userIds := make([]uuid.UUID, 0, len(postEntities))
-	for _, post := range postEntities {
-		userIds = append(userIds, post.UserId)
+	return s.newPostModels(ctx, postEntities)
+}
+
+func (s *PostService) CreatePost(ctx context.Context, userId uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error) {
+	newPost, err := entity.NewPost(userId, title, content, isCommentable)
+
+	if err != nil {
+		return nil, err
 	}
 
-	users, err := s.RepoHolder.UserRepo.GetManyByIds(ctx, userIds)
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
 	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.RepoHolder.PostRepo.Create(ctx, newPost); err != nil {
 		return nil, err
 	}
 
-	usersMap := make(map[uuid.UUID]entity.User, len(users))
-	for _, user := range users {
-		usersMap[user.Id] = user
+	return newPostModel(newPost, user), nil
+}
+
+func (s *PostService) EditPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID, title string, content string) (*model.Post, error) {
+	post, err := s.getOwnPost(ctx, postId, userId)
+	if err != nil {
+		return nil, err
 	}
 
-	posts := make([]*model.Post, 0, len(postEntities))
-	for _, postEntity := range postEntities {
-		userEntity, exists := usersMap[postEntity.UserId]
-		if !exists {
-			return nil, ErrUserNotFound
+	post.Title = title
+	post.Content = content
+	if err := post.Validate(); err != nil {
+		return nil, fmt.Errorf("Validation error: %w", err)
+	}
+
+	return s.updatePost(ctx, post)
+}
+
+func (s *PostService) SaveDraft(ctx context.Context, userId uuid.UUID, postId *uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error) {
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if postId == nil {
+		draft, err := entity.NewDraft(userId, title, content, isCommentable)
+		if err != nil {
+			return nil, fmt.Errorf("Validation error: %w", err)
+		}
+		if err := s.RepoHolder.PostRepo.Create(ctx, draft); err != nil {
+			return nil, err
 		}
+		return newPostModel(draft, user), nil
+	}
+
+	draft, err := s.getOwnPost(ctx, *postId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if draft.IsPublished() {
+		return nil, entity.ErrPostAlreadyPublished
+	}
 
-		posts = append(posts, &model.Post{
-			ID:            postEntity.Id.String(),
-			Title:         postEntity.Title,
-			Content:       postEntity.Content,
-			IsCommentable: postEntity.IsCommentable,
-			CreatedAt:     postEntity.CreatedAt,
-			User: &model.User{
-				ID:       userEntity.Id.String(),
-				Username: userEntity.Username,
-			},
-		})
+	draft.Title = title
+	draft.Content = content
+	draft.IsCommentable = isCommentable
+	if err := draft.Validate(); err != nil {
+		return nil, fmt.Errorf("Validation error: %w", err)
 	}
 
-	return posts, nil
-}
+	if err := s.RepoHolder.PostRepo.Update(ctx, draft); err != nil {
+		return nil, err
+	}
 
-func (s *PostService) CreatePost(ctx context.Context, userId uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error) {
-	newPost, err := entity.NewPost(userId, title, content, isCommentable)
+	return newPostModel(draft, user), nil
+}
 
+func (s *PostService) PublishPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID) (*model.Post, error) {
+	post, err := s.getOwnPost(ctx, postId, userId)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err := post.Publish(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.updatePost(ctx, post)
+}
+
+func (s *PostService) SchedulePost(ctx context.Context, postId uuid.UUID, userId uuid.UUID, publishAt time.Time) (*model.Post, error) {
+	post, err := s.getOwnPost(ctx, postId, userId)
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, err
 	}
 
-	if err := s.RepoHolder.PostRepo.Create(ctx, newPost); err != nil {
+	if err := post.Schedule(publishAt, time.Now()); err != nil {
 		return nil, err
 	}
 
-	return &model.Post{
-		ID:            newPost.Id.String(),
-		Title:         newPost.Title,
-		Content:       newPost.Content,
-		IsCommentable: newPost.IsCommentable,
-		CreatedAt:     newPost.CreatedAt,
-		User: &model.User{
-			ID:       user.Id.String(),
-			Username: user.Username,
-		},
-	}, nil
+	return s.updatePost(ctx, post)
+}
+
+func (s *PostService) PublishDuePosts(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	postEntities, err := s.RepoHolder.PostRepo.PublishDue(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish scheduled posts: %w", err)
+	}
+
+	if len(postEntities) == 0 {
+		return []*model.Post{}, nil
+	}
+
+	return s.newPostModels(ctx, postEntities)
 }
 
 func (s *PostService) TogglePostComments(ctx context.Context, postId uuid.UUID, editorId uuid.UUID, enabled bool) error {
@@ -137,3 +184,185 @@ func (s *PostService) TogglePostComments(ctx context.Context, postId uuid.UUID,
 
 	return nil
 }
+
+func (s *PostService) GetPostsByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID, viewerId *uuid.UUID) ([]*model.Post, error) {
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	includeUnpublished := viewerId != nil && *viewerId == userId
+	postEntities, err := s.RepoHolder.PostRepo.GetByUser(ctx, userId, first, after, includeUnpublished)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user posts: %w", err)
+	}
+
+	posts := make([]*model.Post, 0, len(postEntities))
+	for i := range postEntities {
+		posts = append(posts, newPostModel(&postEntities[i], user))
+	}
+
+	return posts, nil
+}
+
+func (s *PostService) GetRevisions(ctx context.Context, postId uuid.UUID, first int, after *uuid.UUID) ([]*model.PostRevision, error) {
+	revisionEntities, err := s.RepoHolder.PostRepo.GetRevisions(ctx, postId, first, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post revisions: %w", err)
+	}
+
+	revisions := make([]*model.PostRevision, 0, len(revisionEntities))
+	for i := range revisionEntities {
+		revisions = append(revisions, newPostRevisionModel(&revisionEntities[i]))
+	}
+
+	return revisions, nil
+}
+
+func (s *PostService) GetRevision(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.PostRevision, error) {
+	revision, err := s.getVisibleRevision(ctx, id, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPostRevisionModel(revision), nil
+}
+
+// DiffRevisions compares two revisions of the same post. Without toId the
+// revision is compared with the current version of the post.
+func (s *PostService) DiffRevisions(ctx context.Context, fromId uuid.UUID, toId *uuid.UUID, viewerId *uuid.UUID) (*model.PostDiff, error) {
+	from, err := s.getVisibleRevision(ctx, fromId, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var toTitle, toContent string
+	if toId != nil {
+		to, err := s.getVisibleRevision(ctx, *toId, viewerId)
+		if err != nil {
+			return nil, err
+		}
+		if to.PostId != from.PostId {
+			return nil, ErrRevisionsMismatch
+		}
+		toTitle, toContent = to.Title, to.Content
+	} else {
+		post, err := s.RepoHolder.PostRepo.GetOneById(ctx, from.PostId)
+		if err != nil {
+			return nil, ErrPostNotFound
+		}
+		toTitle, toContent = post.Title, post.Content
+	}
+
+	return &model.PostDiff{
+		Title:   newDiffModel(diff.Lines(from.Title, toTitle)),
+		Content: newDiffModel(diff.Lines(from.Content, toContent)),
+	}, nil
+}
+
+func (s *PostService) getVisibleRevision(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*entity.PostRevision, error) {
+	revision, err := s.RepoHolder.PostRepo.GetRevision(ctx, id)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	post, err := s.RepoHolder.PostRepo.GetOneById(ctx, revision.PostId)
+	if err != nil || (!post.IsPublished() && !isAuthor(post, viewerId)) {
+		return nil, ErrRevisionNotFound
+	}
+
+	return revision, nil
+}
+
+func (s *PostService) getOwnPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID) (*entity.Post, error) {
+	post, err := s.RepoHolder.PostRepo.GetOneById(ctx, postId)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrPostNotFound
+		default:
+			return nil, err
+		}
+	}
+	if post.UserId != userId {
+		return nil, ErrNoPermissionForPost
+	}
+	return post, nil
+}
+
+func (s *PostService) updatePost(ctx context.Context, post *entity.Post) (*model.Post, error) {
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, post.UserId)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.RepoHolder.PostRepo.Update(ctx, post); err != nil {
+		return nil, err
+	}
+
+	return newPostModel(post, user), nil
+}
+
+func (s *PostService) newPostModels(ctx context.Context, postEntities []entity.Post) ([]*model.Post, error) {
+	userIds := make([]uuid.UUID, 0, len(postEntities))
+	for _, post := range postEntities {
+		userIds = append(userIds, post.UserId)
+	}
+
+	users, err := s.RepoHolder.UserRepo.GetManyByIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	usersMap := make(map[uuid.UUID]entity.User, len(users))
+	for _, user := range users {
+		usersMap[user.Id] = user
+	}
+
+	posts := make([]*model.Post, 0, len(postEntities))
+	for i := range postEntities {
+		userEntity, exists := usersMap[postEntities[i].UserId]
+		if !exists {
+			return nil, ErrUserNotFound
+		}
+
+		posts = append(posts, newPostModel(&postEntities[i], &userEntity))
+	}
+
+	return posts, nil
+}
+
+func isAuthor(post *entity.Post, viewerId *uuid.UUID) bool {
+	return viewerId != nil && *viewerId == post.UserId
+}
+
+func newPostModel(post *entity.Post, user *entity.User) *model.Post {
+	return &model.Post{
+		ID:            post.Id.String(),
+		Title:         post.Title,
+		Content:       post.Content,
+		IsCommentable: post.IsCommentable,
+		Status:        model.PostStatus(post.Status),
+		PublishAt:     post.PublishAt,
+		CreatedAt:     post.CreatedAt,
+		User:          newUserModel(user),
+	}
+}
+
+func newPostRevisionModel(revision *entity.PostRevision) *model.PostRevision {
+	return &model.PostRevision{
+		ID:        revision.Id.String(),
+		PostID:    revision.PostId.String(),
+		Title:     revision.Title,
+		Content:   revision.Content,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+func newDiffModel(lines []diff.Line) []*model.DiffLine {
+	result := make([]*model.DiffLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, &model.DiffLine{Op: model.DiffOp(line.Op), Text: line.Text})
+	}
+	return result
+}
@@ -36,6 +36,7 @@ func TestPostService_GetPostById(t *testing.T) {
 			Title:         "Test Post",
 			Content:       "Content",
 			IsCommentable: true,
+			Status:        entity.PostStatusPublished,
 			CreatedAt:     time.Now(),
 		}
 		user := &entity.User{
@@ -46,7 +47,7 @@ func TestPostService_GetPostById(t *testing.T) {
 		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(post, nil)
 		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
 
-		result, err := postService.GetPostById(ctx, postId)
+		result, err := postService.GetPostById(ctx, postId, nil)
 		require.NoError(t, err)
 		assert.Equal(t, post.Id.String(), result.ID)
 		assert.Equal(t, post.Title, result.Title)
@@ -56,7 +57,7 @@ func TestPostService_GetPostById(t *testing.T) {
 	t.Run("post not found", func(t *testing.T) {
 		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(nil, repository.ErrNotFound)
 
-		_, err := postService.GetPostById(ctx, postId)
+		_, err := postService.GetPostById(ctx, postId, nil)
 		assert.ErrorIs(t, err, service.ErrPostNotFound)
 	})
 
@@ -64,13 +65,44 @@ func TestPostService_GetPostById(t *testing.T) {
 		post := &entity.Post{
 			Id:     postId,
 			UserId: userId,
+			Status: entity.PostStatusPublished,
 		}
 		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(post, nil)
 		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(nil, repository.ErrNotFound)
 
-		_, err := postService.GetPostById(ctx, postId)
+		_, err := postService.GetPostById(ctx, postId, nil)
 		assert.ErrorIs(t, err, service.ErrUserNotFound)
 	})
+
+	t.Run("draft hidden from other viewers", func(t *testing.T) {
+		draft := &entity.Post{
+			Id:     postId,
+			UserId: userId,
+			Status: entity.PostStatusDraft,
+		}
+		viewerId := uuid.New()
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil).Times(2)
+
+		_, err := postService.GetPostById(ctx, postId, nil)
+		assert.ErrorIs(t, err, service.ErrPostNotFound)
+
+		_, err = postService.GetPostById(ctx, postId, &viewerId)
+		assert.ErrorIs(t, err, service.ErrPostNotFound)
+	})
+
+	t.Run("draft visible to author", func(t *testing.T) {
+		draft := &entity.Post{
+			Id:     postId,
+			UserId: userId,
+			Status: entity.PostStatusDraft,
+		}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(&entity.User{Id: userId}, nil)
+
+		result, err := postService.GetPostById(ctx, postId, &userId)
+		require.NoError(t, err)
+		assert.Equal(t, "DRAFT", string(result.Status))
+	})
 }
 
 func TestPostService_GetPosts(t *testing.T) {
@@ -114,10 +146,10 @@ func TestPostService_GetPosts(t *testing.T) {
 			userId2: {Id: userId2, Username: "user2"},
 		}
 
-		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest).Return(posts, nil)
+		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest, nil).Return(posts, nil)
 		mockUserRepo.EXPECT().GetManyByIds(ctx, []uuid.UUID{userId1, userId2}).Return(users, nil)
 
-		result, err := postService.GetPosts(ctx, limit, offset, nil)
+		result, err := postService.GetPosts(ctx, limit, offset, nil, nil)
 		require.NoError(t, err)
 		require.Len(t, result, 2)
 		assert.Equal(t, postId1.String(), result[0].ID)
@@ -128,40 +160,40 @@ func TestPostService_GetPosts(t *testing.T) {
 
 	t.Run("empty result", func(t *testing.T) {
 		mockPostRepo.EXPECT().
-			GetMany(ctx, limit, offset, repository.SortByNewest).
+			GetMany(ctx, limit, offset, repository.SortByNewest, nil).
 			Return([]entity.Post{}, nil)
 
 		mockUserRepo.EXPECT().
 			GetManyByIds(ctx, []uuid.UUID{}).
 			Return(map[uuid.UUID]entity.User{}, nil)
 
-		result, err := postService.GetPosts(ctx, limit, offset, nil)
+		result, err := postService.GetPosts(ctx, limit, offset, nil, nil)
 		require.NoError(t, err)
 		assert.Empty(t, result)
 	})
 	t.Run("post repo error", func(t *testing.T) {
 		expectedErr := errors.New("post repo error")
-		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest).Return(nil, expectedErr)
+		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest, nil).Return(nil, expectedErr)
 
-		_, err := postService.GetPosts(ctx, limit, offset, nil)
+		_, err := postService.GetPosts(ctx, limit, offset, nil, nil)
 		assert.ErrorIs(t, err, expectedErr)
 	})
 
 	t.Run("user repo error", func(t *testing.T) {
 		posts := []entity.Post{{Id: postId1, UserId: userId1}}
-		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest).Return(posts, nil)
+		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest, nil).Return(posts, nil)
 		mockUserRepo.EXPECT().GetManyByIds(ctx, []uuid.UUID{userId1}).Return(nil, errors.New("user repo error"))
 
-		_, err := postService.GetPosts(ctx, limit, offset, nil)
+		_, err := postService.GetPosts(ctx, limit, offset, nil, nil)
 		assert.Error(t, err)
 	})
 
 	t.Run("missing user", func(t *testing.T) {
 		posts := []entity.Post{{Id: postId1, UserId: userId1}}
-		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest).Return(posts, nil)
+		mockPostRepo.EXPECT().GetMany(ctx, limit, offset, repository.SortByNewest, nil).Return(posts, nil)
 		mockUserRepo.EXPECT().GetManyByIds(ctx, []uuid.UUID{userId1}).Return(map[uuid.UUID]entity.User{}, nil)
 
-		_, err := postService.GetPosts(ctx, limit, offset, nil)
+		_, err := postService.GetPosts(ctx, limit, offset, nil, nil)
 		assert.ErrorIs(t, err, service.ErrUserNotFound)
 	})
 }
@@ -303,3 +335,336 @@ func TestPostService_TogglePostComments(t *testing.T) {
 		assert.ErrorIs(t, err, expectedErr)
 	})
 }
+
+func TestPostService_GetPostsByUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, UserRepo: mockUserRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	userId := uuid.New()
+	after := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		user := &entity.User{Id: userId, Username: "author"}
+		posts := []entity.Post{{Id: uuid.New(), UserId: userId, Title: "Post 1"}}
+
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
+		mockPostRepo.EXPECT().GetByUser(ctx, userId, 10, &after, false).Return(posts, nil)
+
+		result, err := postService.GetPostsByUser(ctx, userId, 10, &after, nil)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, posts[0].Id.String(), result[0].ID)
+		assert.Equal(t, "author", result[0].User.Username)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(nil, repository.ErrNotFound)
+
+		_, err := postService.GetPostsByUser(ctx, userId, 10, nil, nil)
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+	})
+
+	t.Run("author sees drafts", func(t *testing.T) {
+		user := &entity.User{Id: userId, Username: "author"}
+		posts := []entity.Post{{Id: uuid.New(), UserId: userId, Status: entity.PostStatusDraft}}
+
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
+		mockPostRepo.EXPECT().GetByUser(ctx, userId, 10, nil, true).Return(posts, nil)
+
+		result, err := postService.GetPostsByUser(ctx, userId, 10, nil, &userId)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
+
+func TestPostService_SaveDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, UserRepo: mockUserRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	userId := uuid.New()
+	postId := uuid.New()
+	user := &entity.User{Id: userId, Username: "author"}
+
+	t.Run("new draft", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
+		mockPostRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		result, err := postService.SaveDraft(ctx, userId, nil, "Title", "Content", true)
+		require.NoError(t, err)
+		assert.Equal(t, "DRAFT", string(result.Status))
+		assert.Nil(t, result.PublishAt)
+	})
+
+	t.Run("update existing draft", func(t *testing.T) {
+		draft := &entity.Post{Id: postId, UserId: userId, Title: "Old", Content: "Old", Status: entity.PostStatusDraft}
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+		mockPostRepo.EXPECT().Update(ctx, draft).Return(nil)
+
+		result, err := postService.SaveDraft(ctx, userId, &postId, "New", "New content", false)
+		require.NoError(t, err)
+		assert.Equal(t, "New", result.Title)
+		assert.False(t, result.IsCommentable)
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		draft := &entity.Post{Id: postId, UserId: uuid.New(), Status: entity.PostStatusDraft}
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+
+		_, err := postService.SaveDraft(ctx, userId, &postId, "New", "New", true)
+		assert.ErrorIs(t, err, service.ErrNoPermissionForPost)
+	})
+
+	t.Run("already published", func(t *testing.T) {
+		post := &entity.Post{Id: postId, UserId: userId, Status: entity.PostStatusPublished}
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(user, nil)
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(post, nil)
+
+		_, err := postService.SaveDraft(ctx, userId, &postId, "New", "New", true)
+		assert.ErrorIs(t, err, entity.ErrPostAlreadyPublished)
+	})
+}
+
+func TestPostService_PublishPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, UserRepo: mockUserRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	userId := uuid.New()
+	postId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		draft := &entity.Post{Id: postId, UserId: userId, Status: entity.PostStatusDraft}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(&entity.User{Id: userId}, nil)
+		mockPostRepo.EXPECT().Update(ctx, draft).Return(nil)
+
+		result, err := postService.PublishPost(ctx, postId, userId)
+		require.NoError(t, err)
+		assert.Equal(t, "PUBLISHED", string(result.Status))
+		assert.NotNil(t, result.PublishAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(nil, repository.ErrNotFound)
+
+		_, err := postService.PublishPost(ctx, postId, userId)
+		assert.ErrorIs(t, err, service.ErrPostNotFound)
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		draft := &entity.Post{Id: postId, UserId: uuid.New(), Status: entity.PostStatusDraft}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+
+		_, err := postService.PublishPost(ctx, postId, userId)
+		assert.ErrorIs(t, err, service.ErrNoPermissionForPost)
+	})
+}
+
+func TestPostService_SchedulePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, UserRepo: mockUserRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	userId := uuid.New()
+	postId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		draft := &entity.Post{Id: postId, UserId: userId, Status: entity.PostStatusDraft}
+		publishAt := time.Now().Add(time.Hour)
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(&entity.User{Id: userId}, nil)
+		mockPostRepo.EXPECT().Update(ctx, draft).Return(nil)
+
+		result, err := postService.SchedulePost(ctx, postId, userId, publishAt)
+		require.NoError(t, err)
+		assert.Equal(t, "SCHEDULED", string(result.Status))
+		assert.True(t, publishAt.Equal(*result.PublishAt))
+	})
+
+	t.Run("time in the past", func(t *testing.T) {
+		draft := &entity.Post{Id: postId, UserId: userId, Status: entity.PostStatusDraft}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(draft, nil)
+
+		_, err := postService.SchedulePost(ctx, postId, userId, time.Now().Add(-time.Hour))
+		assert.ErrorIs(t, err, entity.ErrPublishTimeInPast)
+	})
+}
+
+func TestPostService_PublishDuePosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, UserRepo: mockUserRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	now := time.Now()
+	userId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		posts := []entity.Post{{Id: uuid.New(), UserId: userId, Status: entity.PostStatusPublished}}
+		mockPostRepo.EXPECT().PublishDue(ctx, now).Return(posts, nil)
+		mockUserRepo.EXPECT().GetManyByIds(ctx, []uuid.UUID{userId}).
+			Return(map[uuid.UUID]entity.User{userId: {Id: userId}}, nil)
+
+		result, err := postService.PublishDuePosts(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, posts[0].Id.String(), result[0].ID)
+	})
+
+	t.Run("nothing due", func(t *testing.T) {
+		mockPostRepo.EXPECT().PublishDue(ctx, now).Return(nil, nil)
+
+		result, err := postService.PublishDuePosts(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestPostService_EditPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, UserRepo: mockUserRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	userId := uuid.New()
+	postId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		post := &entity.Post{Id: postId, UserId: userId, Title: "Old", Content: "Old", Status: entity.PostStatusPublished, PublishAt: &time.Time{}}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(post, nil)
+		mockUserRepo.EXPECT().GetOneById(ctx, userId).Return(&entity.User{Id: userId}, nil)
+		mockPostRepo.EXPECT().Update(ctx, post).Return(nil)
+
+		result, err := postService.EditPost(ctx, postId, userId, "New", "New content")
+		require.NoError(t, err)
+		assert.Equal(t, "New", result.Title)
+		assert.Equal(t, "New content", result.Content)
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		post := &entity.Post{Id: postId, UserId: uuid.New(), Status: entity.PostStatusPublished}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(post, nil)
+
+		_, err := postService.EditPost(ctx, postId, userId, "New", "New content")
+		assert.ErrorIs(t, err, service.ErrNoPermissionForPost)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		post := &entity.Post{Id: postId, UserId: userId, Status: entity.PostStatusPublished, PublishAt: &time.Time{}}
+		mockPostRepo.EXPECT().GetOneById(ctx, postId).Return(post, nil)
+
+		_, err := postService.EditPost(ctx, postId, userId, "", "New content")
+		assert.ErrorIs(t, err, entity.ErrEmptyTitle)
+	})
+}
+
+func TestPostService_Revisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo}
+	postService := &service.PostService{RepoHolder: repoHolder}
+
+	ctx := context.Background()
+	authorId := uuid.New()
+	post := &entity.Post{
+		Id:      uuid.New(),
+		UserId:  authorId,
+		Title:   "Title",
+		Content: "a\nc",
+		Status:  entity.PostStatusPublished,
+	}
+	revision := &entity.PostRevision{Id: uuid.New(), PostId: post.Id, Title: "Title", Content: "a\nb"}
+
+	t.Run("GetRevisions", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetRevisions(ctx, post.Id, 10, nil).Return([]entity.PostRevision{*revision}, nil)
+
+		result, err := postService.GetRevisions(ctx, post.Id, 10, nil)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, revision.Id.String(), result[0].ID)
+		assert.Equal(t, post.Id.String(), result[0].PostID)
+	})
+
+	t.Run("GetRevision not found", func(t *testing.T) {
+		id := uuid.New()
+		mockPostRepo.EXPECT().GetRevision(ctx, id).Return(nil, repository.ErrNotFound)
+
+		_, err := postService.GetRevision(ctx, id, nil)
+		assert.ErrorIs(t, err, service.ErrRevisionNotFound)
+	})
+
+	t.Run("GetRevision of a draft", func(t *testing.T) {
+		draft := &entity.Post{Id: post.Id, UserId: authorId, Status: entity.PostStatusDraft}
+		mockPostRepo.EXPECT().GetRevision(ctx, revision.Id).Return(revision, nil).Times(2)
+		mockPostRepo.EXPECT().GetOneById(ctx, post.Id).Return(draft, nil).Times(2)
+
+		_, err := postService.GetRevision(ctx, revision.Id, nil)
+		assert.ErrorIs(t, err, service.ErrRevisionNotFound)
+
+		result, err := postService.GetRevision(ctx, revision.Id, &authorId)
+		require.NoError(t, err)
+		assert.Equal(t, revision.Id.String(), result.ID)
+	})
+
+	t.Run("diff against current post", func(t *testing.T) {
+		mockPostRepo.EXPECT().GetRevision(ctx, revision.Id).Return(revision, nil)
+		mockPostRepo.EXPECT().GetOneById(ctx, post.Id).Return(post, nil).Times(2)
+
+		result, err := postService.DiffRevisions(ctx, revision.Id, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Content, 3)
+		assert.Equal(t, "EQUAL", string(result.Content[0].Op))
+		assert.Equal(t, "DELETE", string(result.Content[1].Op))
+		assert.Equal(t, "b", result.Content[1].Text)
+		assert.Equal(t, "INSERT", string(result.Content[2].Op))
+		assert.Equal(t, "c", result.Content[2].Text)
+		require.Len(t, result.Title, 1)
+		assert.Equal(t, "EQUAL", string(result.Title[0].Op))
+	})
+
+	t.Run("diff revisions of different posts", func(t *testing.T) {
+		other := &entity.PostRevision{Id: uuid.New(), PostId: uuid.New()}
+		otherPost := &entity.Post{Id: other.PostId, Status: entity.PostStatusPublished}
+		mockPostRepo.EXPECT().GetRevision(ctx, revision.Id).Return(revision, nil)
+		mockPostRepo.EXPECT().GetOneById(ctx, post.Id).Return(post, nil)
+		mockPostRepo.EXPECT().GetRevision(ctx, other.Id).Return(other, nil)
+		mockPostRepo.EXPECT().GetOneById(ctx, other.PostId).Return(otherPost, nil)
+
+		_, err := postService.DiffRevisions(ctx, revision.Id, &other.Id, nil)
+		assert.ErrorIs(t, err, service.ErrRevisionsMismatch)
+	})
+}
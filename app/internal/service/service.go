@@ -3,6 +3,8 @@ package service
 import (
 	"app/graph/model"
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,23 +14,55 @@ import (
 type User interface {
 	GetUser(ctx context.Context, id uuid.UUID) (*model.User, error)
 	CreateUser(ctx context.Context, username string) (*model.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
+	GetUserStats(ctx context.Context, id uuid.UUID) (*model.UserStats, error)
+	UpdateProfile(ctx context.Context, userId uuid.UUID, displayName *string, bio *string, avatar *FileUpload) (*model.User, error)
 }
 
 type Post interface {
-	GetPostById(ctx context.Context, id uuid.UUID) (*model.Post, error)
-	GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy) ([]*model.Post, error)
+	GetPostById(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.Post, error)
+	GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy, viewerId *uuid.UUID) ([]*model.Post, error)
 	CreatePost(ctx context.Context, userId uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error)
+	EditPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID, title string, content string) (*model.Post, error)
+	SaveDraft(ctx context.Context, userId uuid.UUID, postId *uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error)
+	PublishPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID) (*model.Post, error)
+	SchedulePost(ctx context.Context, postId uuid.UUID, userId uuid.UUID, publishAt time.Time) (*model.Post, error)
+	PublishDuePosts(ctx context.Context, now time.Time) ([]*model.Post, error)
 	TogglePostComments(ctx context.Context, postId uuid.UUID, editorId uuid.UUID, enabled bool) error
+	GetPostsByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID, viewerId *uuid.UUID) ([]*model.Post, error)
+	GetRevisions(ctx context.Context, postId uuid.UUID, first int, after *uuid.UUID) ([]*model.PostRevision, error)
+	GetRevision(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.PostRevision, error)
+	DiffRevisions(ctx context.Context, fromId uuid.UUID, toId *uuid.UUID, viewerId *uuid.UUID) (*model.PostDiff, error)
 }
 
 type Comment interface {
 	CreateComment(ctx context.Context, userId uuid.UUID, postId uuid.UUID, parentId *uuid.UUID, content string) (*model.Comment, error)
 	GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*model.Comment, error)
 	GetCommentReplies(ctx context.Context, parentId uuid.UUID, limit, offset int) ([]*model.Comment, error)
+	GetAncestors(ctx context.Context, commentId uuid.UUID) ([]*model.Comment, error)
+	GetByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID) ([]*model.Comment, error)
+	EditComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, content string) (*model.Comment, error)
+	GetHistory(ctx context.Context, commentId uuid.UUID, viewerId uuid.UUID) ([]*model.CommentEdit, error)
+	SetThreadLocked(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, locked bool) (*model.Comment, error)
+}
+
+type Attachment interface {
+	AddPostAttachment(ctx context.Context, postId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error)
+	AddCommentAttachment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error)
+	GetByPost(ctx context.Context, postId uuid.UUID) ([]*model.Attachment, error)
+	GetByComment(ctx context.Context, commentId uuid.UUID) ([]*model.Attachment, error)
+}
+
+type FileUpload struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
 }
 
 type Services struct {
 	Comment
 	Post
 	User
+	Attachment
 }
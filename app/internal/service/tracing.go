@@ -0,0 +1,224 @@
+package service
+
+import (
+	"app/graph/model"
+	"app/internal/tracing"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WithTracing wraps every service so that each call is recorded as a span.
+func WithTracing(services *Services) *Services {
+	return &Services{
+		Comment:    tracingComment{services.Comment},
+		Post:       tracingPost{services.Post},
+		User:       tracingUser{services.User},
+		Attachment: tracingAttachment{services.Attachment},
+	}
+}
+
+func traced[T any](ctx context.Context, name string, call func(context.Context) (T, error)) (T, error) {
+	ctx, span := tracing.Start(ctx, name)
+	res, err := call(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+type tracingUser struct {
+	next User
+}
+
+func (s tracingUser) GetUser(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	return traced(ctx, "UserService.GetUser", func(ctx context.Context) (*model.User, error) {
+		return s.next.GetUser(ctx, id)
+	})
+}
+
+func (s tracingUser) CreateUser(ctx context.Context, username string) (*model.User, error) {
+	return traced(ctx, "UserService.CreateUser", func(ctx context.Context) (*model.User, error) {
+		return s.next.CreateUser(ctx, username)
+	})
+}
+
+func (s tracingUser) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	return traced(ctx, "UserService.GetUserByUsername", func(ctx context.Context) (*model.User, error) {
+		return s.next.GetUserByUsername(ctx, username)
+	})
+}
+
+func (s tracingUser) GetUserStats(ctx context.Context, id uuid.UUID) (*model.UserStats, error) {
+	return traced(ctx, "UserService.GetUserStats", func(ctx context.Context) (*model.UserStats, error) {
+		return s.next.GetUserStats(ctx, id)
+	})
+}
+
+func (s tracingUser) UpdateProfile(ctx context.Context, userId uuid.UUID, displayName *string, bio *string, avatar *FileUpload) (*model.User, error) {
+	return traced(ctx, "UserService.UpdateProfile", func(ctx context.Context) (*model.User, error) {
+		return s.next.UpdateProfile(ctx, userId, displayName, bio, avatar)
+	})
+}
+
+type tracingPost struct {
+	next Post
+}
+
+func (s tracingPost) GetPostById(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.Post, error) {
+	return traced(ctx, "PostService.GetPostById", func(ctx context.Context) (*model.Post, error) {
+		return s.next.GetPostById(ctx, id, viewerId)
+	})
+}
+
+func (s tracingPost) GetPosts(ctx context.Context, limit, offset int, sortBy *model.SortBy, viewerId *uuid.UUID) ([]*model.Post, error) {
+	return traced(ctx, "PostService.GetPosts", func(ctx context.Context) ([]*model.Post, error) {
+		return s.next.GetPosts(ctx, limit, offset, sortBy, viewerId)
+	})
+}
+
+func (s tracingPost) CreatePost(ctx context.Context, userId uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error) {
+	return traced(ctx, "PostService.CreatePost", func(ctx context.Context) (*model.Post, error) {
+		return s.next.CreatePost(ctx, userId, title, content, isCommentable)
+	})
+}
+
+func (s tracingPost) EditPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID, title string, content string) (*model.Post, error) {
+	return traced(ctx, "PostService.EditPost", func(ctx context.Context) (*model.Post, error) {
+		return s.next.EditPost(ctx, postId, userId, title, content)
+	})
+}
+
+func (s tracingPost) SaveDraft(ctx context.Context, userId uuid.UUID, postId *uuid.UUID, title string, content string, isCommentable bool) (*model.Post, error) {
+	return traced(ctx, "PostService.SaveDraft", func(ctx context.Context) (*model.Post, error) {
+		return s.next.SaveDraft(ctx, userId, postId, title, content, isCommentable)
+	})
+}
+
+func (s tracingPost) PublishPost(ctx context.Context, postId uuid.UUID, userId uuid.UUID) (*model.Post, error) {
+	return traced(ctx, "PostService.PublishPost", func(ctx context.Context) (*model.Post, error) {
+		return s.next.PublishPost(ctx, postId, userId)
+	})
+}
+
+func (s tracingPost) SchedulePost(ctx context.Context, postId uuid.UUID, userId uuid.UUID, publishAt time.Time) (*model.Post, error) {
+	return traced(ctx, "PostService.SchedulePost", func(ctx context.Context) (*model.Post, error) {
+		return s.next.SchedulePost(ctx, postId, userId, publishAt)
+	})
+}
+
+func (s tracingPost) PublishDuePosts(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	return traced(ctx, "PostService.PublishDuePosts", func(ctx context.Context) ([]*model.Post, error) {
+		return s.next.PublishDuePosts(ctx, now)
+	})
+}
+
+func (s tracingPost) TogglePostComments(ctx context.Context, postId uuid.UUID, editorId uuid.UUID, enabled bool) error {
+	ctx, span := tracing.Start(ctx, "PostService.TogglePostComments")
+	err := s.next.TogglePostComments(ctx, postId, editorId, enabled)
+	tracing.End(span, err)
+	return err
+}
+
+func (s tracingPost) GetPostsByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID, viewerId *uuid.UUID) ([]*model.Post, error) {
+	return traced(ctx, "PostService.GetPostsByUser", func(ctx context.Context) ([]*model.Post, error) {
+		return s.next.GetPostsByUser(ctx, userId, first, after, viewerId)
+	})
+}
+
+func (s tracingPost) GetRevisions(ctx context.Context, postId uuid.UUID, first int, after *uuid.UUID) ([]*model.PostRevision, error) {
+	return traced(ctx, "PostService.GetRevisions", func(ctx context.Context) ([]*model.PostRevision, error) {
+		return s.next.GetRevisions(ctx, postId, first, after)
+	})
+}
+
+func (s tracingPost) GetRevision(ctx context.Context, id uuid.UUID, viewerId *uuid.UUID) (*model.PostRevision, error) {
+	return traced(ctx, "PostService.GetRevision", func(ctx context.Context) (*model.PostRevision, error) {
+		return s.next.GetRevision(ctx, id, viewerId)
+	})
+}
+
+func (s tracingPost) DiffRevisions(ctx context.Context, fromId uuid.UUID, toId *uuid.UUID, viewerId *uuid.UUID) (*model.PostDiff, error) {
+	return traced(ctx, "PostService.DiffRevisions", func(ctx context.Context) (*model.PostDiff, error) {
+		return s.next.DiffRevisions(ctx, fromId, toId, viewerId)
+	})
+}
+
+type tracingComment struct {
+	next Comment
+}
+
+func (s tracingComment) CreateComment(ctx context.Context, userId uuid.UUID, postId uuid.UUID, parentId *uuid.UUID, content string) (*model.Comment, error) {
+	return traced(ctx, "CommentService.CreateComment", func(ctx context.Context) (*model.Comment, error) {
+		return s.next.CreateComment(ctx, userId, postId, parentId, content)
+	})
+}
+
+func (s tracingComment) GetByPost(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*model.Comment, error) {
+	return traced(ctx, "CommentService.GetByPost", func(ctx context.Context) ([]*model.Comment, error) {
+		return s.next.GetByPost(ctx, postId, limit, offset)
+	})
+}
+
+func (s tracingComment) GetCommentReplies(ctx context.Context, parentId uuid.UUID, limit, offset int) ([]*model.Comment, error) {
+	return traced(ctx, "CommentService.GetCommentReplies", func(ctx context.Context) ([]*model.Comment, error) {
+		return s.next.GetCommentReplies(ctx, parentId, limit, offset)
+	})
+}
+
+func (s tracingComment) GetAncestors(ctx context.Context, commentId uuid.UUID) ([]*model.Comment, error) {
+	return traced(ctx, "CommentService.GetAncestors", func(ctx context.Context) ([]*model.Comment, error) {
+		return s.next.GetAncestors(ctx, commentId)
+	})
+}
+
+func (s tracingComment) GetByUser(ctx context.Context, userId uuid.UUID, first int, after *uuid.UUID) ([]*model.Comment, error) {
+	return traced(ctx, "CommentService.GetByUser", func(ctx context.Context) ([]*model.Comment, error) {
+		return s.next.GetByUser(ctx, userId, first, after)
+	})
+}
+
+func (s tracingComment) EditComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, content string) (*model.Comment, error) {
+	return traced(ctx, "CommentService.EditComment", func(ctx context.Context) (*model.Comment, error) {
+		return s.next.EditComment(ctx, commentId, userId, content)
+	})
+}
+
+func (s tracingComment) GetHistory(ctx context.Context, commentId uuid.UUID, viewerId uuid.UUID) ([]*model.CommentEdit, error) {
+	return traced(ctx, "CommentService.GetHistory", func(ctx context.Context) ([]*model.CommentEdit, error) {
+		return s.next.GetHistory(ctx, commentId, viewerId)
+	})
+}
+
+func (s tracingComment) SetThreadLocked(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, locked bool) (*model.Comment, error) {
+	return traced(ctx, "CommentService.SetThreadLocked", func(ctx context.Context) (*model.Comment, error) {
+		return s.next.SetThreadLocked(ctx, commentId, userId, locked)
+	})
+}
+
+type tracingAttachment struct {
+	next Attachment
+}
+
+func (s tracingAttachment) AddPostAttachment(ctx context.Context, postId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error) {
+	return traced(ctx, "AttachmentService.AddPostAttachment", func(ctx context.Context) (*model.Attachment, error) {
+		return s.next.AddPostAttachment(ctx, postId, userId, file)
+	})
+}
+
+func (s tracingAttachment) AddCommentAttachment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID, file *FileUpload) (*model.Attachment, error) {
+	return traced(ctx, "AttachmentService.AddCommentAttachment", func(ctx context.Context) (*model.Attachment, error) {
+		return s.next.AddCommentAttachment(ctx, commentId, userId, file)
+	})
+}
+
+func (s tracingAttachment) GetByPost(ctx context.Context, postId uuid.UUID) ([]*model.Attachment, error) {
+	return traced(ctx, "AttachmentService.GetByPost", func(ctx context.Context) ([]*model.Attachment, error) {
+		return s.next.GetByPost(ctx, postId)
+	})
+}
+
+func (s tracingAttachment) GetByComment(ctx context.Context, commentId uuid.UUID) ([]*model.Attachment, error) {
+	return traced(ctx, "AttachmentService.GetByComment", func(ctx context.Context) ([]*model.Attachment, error) {
+		return s.next.GetByComment(ctx, commentId)
+	})
+}
@@ -2,18 +2,34 @@ package service
 
 import (
 	"app/graph/model"
+	"app/internal/blobstore"
 	"app/internal/entity"
 	"app/internal/repository"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+const maxAvatarSize int64 = 2 << 20
+
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
 type UserService struct {
 	RepoHolder *repository.RepoHolder
+	BlobStore  blobstore.BlobStore
+	Logger     *slog.Logger
 }
 
 func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*model.User, error) {
@@ -26,10 +42,7 @@ func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*model.User, e
 			return nil, err
 		}
 	}
-	return &model.User{
-		ID:       user.Id.String(),
-		Username: user.Username,
-	}, nil
+	return newUserModel(user), nil
 }
 
 func (s *UserService) CreateUser(ctx context.Context, username string) (*model.User, error) {
@@ -39,8 +52,7 @@ func (s *UserService) CreateUser(ctx context.Context, username string) (*model.U
 		case errors.Is(err, repository.ErrNotFound):
 			// pass
 		default:
-			log.Fatalf("%v", err)
-			return nil, err
+			return nil, fmt.Errorf("failed to check username: %w", err)
 		}
 	}
 
@@ -54,12 +66,132 @@ func (s *UserService) CreateUser(ctx context.Context, username string) (*model.U
 	}
 
 	if err := s.RepoHolder.UserRepo.Create(ctx, newUser); err != nil {
-		log.Fatalf("%v", err)
-		return nil, ErrDueUserCreation
+		return nil, fmt.Errorf("%w: %w", ErrDueUserCreation, err)
+	}
+
+	return newUserModel(newUser), nil
+}
+
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	user, err := s.RepoHolder.UserRepo.GetOneByUsername(ctx, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrUserNotFound
+		default:
+			return nil, err
+		}
+	}
+	return newUserModel(user), nil
+}
+
+func (s *UserService) GetUserStats(ctx context.Context, id uuid.UUID) (*model.UserStats, error) {
+	postCount, err := s.RepoHolder.PostRepo.CountByUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count posts: %w", err)
 	}
 
-	return &model.User{
-		ID:       newUser.Id.String(),
-		Username: newUser.Username,
+	commentCount, err := s.RepoHolder.CommentRepo.CountByUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return &model.UserStats{
+		PostCount:    int32(postCount),
+		CommentCount: int32(commentCount),
 	}, nil
 }
+
+func (s *UserService) UpdateProfile(ctx context.Context, userId uuid.UUID, displayName *string, bio *string, avatar *FileUpload) (*model.User, error) {
+	user, err := s.RepoHolder.UserRepo.GetOneById(ctx, userId)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrUserNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if displayName != nil {
+		user.DisplayName = strings.TrimSpace(*displayName)
+	}
+	if bio != nil {
+		user.Bio = strings.TrimSpace(*bio)
+	}
+	if err := user.Validate(); err != nil {
+		return nil, fmt.Errorf("Validation error: %w", err)
+	}
+
+	oldAvatarKey := user.AvatarKey
+	if avatar != nil {
+		user.AvatarKey, err = s.storeAvatar(ctx, userId, avatar)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.RepoHolder.UserRepo.Update(ctx, user); err != nil {
+		if avatar != nil {
+			s.deleteBlob(ctx, user.AvatarKey)
+		}
+		return nil, err
+	}
+
+	if avatar != nil && oldAvatarKey != "" {
+		s.deleteBlob(ctx, oldAvatarKey)
+	}
+
+	return newUserModel(user), nil
+}
+
+func (s *UserService) storeAvatar(ctx context.Context, userId uuid.UUID, avatar *FileUpload) (string, error) {
+	if avatar.Size > maxAvatarSize {
+		return "", ErrAvatarTooLarge
+	}
+
+	// The declared size comes from the client, so the limit is enforced
+	// on the bytes actually read as well.
+	data, err := io.ReadAll(io.LimitReader(avatar.Content, maxAvatarSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if int64(len(data)) > maxAvatarSize {
+		return "", ErrAvatarTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return "", ErrUnsupportedAvatarType
+	}
+
+	key := fmt.Sprintf("avatars/%s/%s%s", userId, uuid.New(), ext)
+	if err := s.BlobStore.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return "", fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	return key, nil
+}
+
+func (s *UserService) deleteBlob(ctx context.Context, key string) {
+	if err := s.BlobStore.Delete(ctx, key); err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+		s.Logger.WarnContext(ctx, "Failed to delete blob", "key", key, "error", err)
+	}
+}
+
+func newUserModel(user *entity.User) *model.User {
+	result := &model.User{
+		ID:        user.Id.String(),
+		Username:  user.Username,
+		AvatarKey: user.AvatarKey,
+		CreatedAt: user.CreatedAt,
+	}
+	if user.DisplayName != "" {
+		result.DisplayName = &user.DisplayName
+	}
+	if user.Bio != "" {
+		result.Bio = &user.Bio
+	}
+	return result
+}
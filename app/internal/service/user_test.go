@@ -1,18 +1,26 @@
 package service_test
 
 import (
+	mock_blobstore "app/internal/blobstore/mocks"
 	"app/internal/entity"
 	"app/internal/repository"
 	mock_repository "app/internal/repository/mocks"
 	"app/internal/service"
 	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 func TestUserService_GetUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -98,4 +106,262 @@ func TestUserService_CreateUser(t *testing.T) {
 		assert.ErrorIs(t, err, service.ErrUsernameExists)
 		assert.Nil(t, result)
 	})
+
+	t.Run("lookup fails", func(t *testing.T) {
+		dbErr := errors.New("connection refused")
+
+		mockUserRepo.EXPECT().
+			GetOneByUsername(gomock.Any(), "newuser").
+			Return(nil, dbErr)
+
+		result, err := userService.CreateUser(context.Background(), "newuser")
+
+		assert.ErrorIs(t, err, dbErr)
+		assert.Nil(t, result)
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		dbErr := errors.New("connection refused")
+
+		mockUserRepo.EXPECT().
+			GetOneByUsername(gomock.Any(), "newuser").
+			Return(nil, repository.ErrNotFound)
+
+		mockUserRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			Return(dbErr)
+
+		result, err := userService.CreateUser(context.Background(), "newuser")
+
+		assert.ErrorIs(t, err, service.ErrDueUserCreation)
+		assert.ErrorIs(t, err, dbErr)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUserService_GetUserByUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	repoHolder := &repository.RepoHolder{UserRepo: mockUserRepo}
+	userService := &service.UserService{RepoHolder: repoHolder}
+
+	t.Run("success", func(t *testing.T) {
+		expectedUser := entity.User{
+			Id:        uuid.New(),
+			Username:  "testuser",
+			CreatedAt: time.Now(),
+		}
+
+		mockUserRepo.EXPECT().
+			GetOneByUsername(gomock.Any(), "testuser").
+			Return(&expectedUser, nil)
+
+		result, err := userService.GetUserByUsername(context.Background(), "testuser")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUser.Id.String(), result.ID)
+		assert.Equal(t, expectedUser.CreatedAt, result.CreatedAt)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneByUsername(gomock.Any(), "missing").
+			Return(nil, repository.ErrNotFound)
+
+		result, err := userService.GetUserByUsername(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUserService_GetUserStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostRepo := mock_repository.NewMockPostRepo(ctrl)
+	mockCommentRepo := mock_repository.NewMockCommentRepo(ctrl)
+	repoHolder := &repository.RepoHolder{PostRepo: mockPostRepo, CommentRepo: mockCommentRepo}
+	userService := &service.UserService{RepoHolder: repoHolder}
+
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockPostRepo.EXPECT().CountByUser(gomock.Any(), userID).Return(2, nil)
+		mockCommentRepo.EXPECT().CountByUser(gomock.Any(), userID).Return(7, nil)
+
+		result, err := userService.GetUserStats(context.Background(), userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), result.PostCount)
+		assert.Equal(t, int32(7), result.CommentCount)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		expectedErr := errors.New("count error")
+		mockPostRepo.EXPECT().CountByUser(gomock.Any(), userID).Return(0, expectedErr)
+
+		result, err := userService.GetUserStats(context.Background(), userID)
+
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Nil(t, result)
+	})
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepo(ctrl)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(ctrl)
+	repoHolder := &repository.RepoHolder{UserRepo: mockUserRepo}
+	userService := &service.UserService{RepoHolder: repoHolder, BlobStore: mockBlobStore, Logger: testLogger}
+
+	userID := uuid.New()
+	pngHeader := "\x89PNG\r\n\x1a\n"
+	displayName := "Test User"
+	bio := "About me"
+
+	t.Run("success without avatar", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser"}, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, user *entity.User) {
+				assert.Equal(t, displayName, user.DisplayName)
+				assert.Equal(t, bio, user.Bio)
+			}).
+			Return(nil)
+
+		result, err := userService.UpdateProfile(context.Background(), userID, &displayName, &bio, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, displayName, *result.DisplayName)
+		assert.Equal(t, bio, *result.Bio)
+	})
+
+	t.Run("success with avatar replaces old one", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser", AvatarKey: "avatars/old.png"}, nil)
+		mockBlobStore.EXPECT().
+			Put(gomock.Any(), gomock.Any(), gomock.Any(), "image/png").
+			Return(nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			Return(nil)
+		mockBlobStore.EXPECT().
+			Delete(gomock.Any(), "avatars/old.png").
+			Return(nil)
+
+		avatar := &service.FileUpload{
+			Filename: "avatar.png",
+			Size:     int64(len(pngHeader)),
+			Content:  strings.NewReader(pngHeader),
+		}
+		result, err := userService.UpdateProfile(context.Background(), userID, nil, nil, avatar)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.AvatarKey, "avatars/"+userID.String()+"/"))
+		assert.True(t, strings.HasSuffix(result.AvatarKey, ".png"))
+	})
+
+	t.Run("failing to delete old avatar is only logged", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser", AvatarKey: "avatars/old.png"}, nil)
+		mockBlobStore.EXPECT().
+			Put(gomock.Any(), gomock.Any(), gomock.Any(), "image/png").
+			Return(nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			Return(nil)
+		mockBlobStore.EXPECT().
+			Delete(gomock.Any(), "avatars/old.png").
+			Return(errors.New("storage unavailable"))
+
+		avatar := &service.FileUpload{
+			Filename: "avatar.png",
+			Size:     int64(len(pngHeader)),
+			Content:  strings.NewReader(pngHeader),
+		}
+		result, err := userService.UpdateProfile(context.Background(), userID, nil, nil, avatar)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+	})
+
+	t.Run("unsupported avatar type", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser"}, nil)
+
+		avatar := &service.FileUpload{
+			Filename: "avatar.png",
+			Size:     4,
+			Content:  strings.NewReader("text"),
+		}
+		result, err := userService.UpdateProfile(context.Background(), userID, nil, nil, avatar)
+
+		assert.ErrorIs(t, err, service.ErrUnsupportedAvatarType)
+		assert.Nil(t, result)
+	})
+
+	t.Run("avatar too large", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser"}, nil)
+
+		avatar := &service.FileUpload{
+			Filename: "avatar.png",
+			Size:     10 << 20,
+			Content:  strings.NewReader(pngHeader),
+		}
+		result, err := userService.UpdateProfile(context.Background(), userID, nil, nil, avatar)
+
+		assert.ErrorIs(t, err, service.ErrAvatarTooLarge)
+		assert.Nil(t, result)
+	})
+
+	t.Run("avatar larger than declared", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser"}, nil)
+
+		avatar := &service.FileUpload{
+			Filename: "avatar.png",
+			Size:     int64(len(pngHeader)),
+			Content:  strings.NewReader(pngHeader + strings.Repeat("x", 2<<20)),
+		}
+		result, err := userService.UpdateProfile(context.Background(), userID, nil, nil, avatar)
+
+		assert.ErrorIs(t, err, service.ErrAvatarTooLarge)
+		assert.Nil(t, result)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(&entity.User{Id: userID, Username: "testuser"}, nil)
+
+		longName := strings.Repeat("a", 100)
+		result, err := userService.UpdateProfile(context.Background(), userID, &longName, nil, nil)
+
+		assert.ErrorIs(t, err, entity.ErrDisplayNameTooLong)
+		assert.Nil(t, result)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetOneById(gomock.Any(), userID).
+			Return(nil, repository.ErrNotFound)
+
+		result, err := userService.UpdateProfile(context.Background(), userID, &displayName, nil, nil)
+
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+		assert.Nil(t, result)
+	})
 }
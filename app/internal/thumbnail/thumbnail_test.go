@@ -0,0 +1,67 @@
+package thumbnail_test
+
+import (
+	"app/internal/thumbnail"
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("scales landscape image", func(t *testing.T) {
+		data, err := thumbnail.Generate(encodePNG(t, 400, 200), 100)
+		require.NoError(t, err)
+
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, 100, cfg.Width)
+		assert.Equal(t, 50, cfg.Height)
+	})
+
+	t.Run("scales portrait jpeg", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 30, 300)), nil))
+
+		data, err := thumbnail.Generate(buf.Bytes(), 100)
+		require.NoError(t, err)
+
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, 10, cfg.Width)
+		assert.Equal(t, 100, cfg.Height)
+	})
+
+	t.Run("keeps small image size", func(t *testing.T) {
+		data, err := thumbnail.Generate(encodePNG(t, 20, 10), 100)
+		require.NoError(t, err)
+
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, 20, cfg.Width)
+		assert.Equal(t, 10, cfg.Height)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := thumbnail.Generate([]byte("%PDF-1.4 not an image"), 100)
+		assert.ErrorIs(t, err, thumbnail.ErrUnsupportedFormat)
+	})
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Extension opens a span for every GraphQL operation and a child span for
+// every resolver field.
+type Extension struct{}
+
+var (
+	_ graphql.HandlerExtension     = Extension{}
+	_ graphql.OperationInterceptor = Extension{}
+	_ graphql.FieldInterceptor     = Extension{}
+)
+
+func (e Extension) ExtensionName() string {
+	return "Tracing"
+}
+
+func (e Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+
+	name, kind := "anonymous", "operation"
+	if op := oc.Operation; op != nil {
+		kind = string(op.Operation)
+		if op.Name != "" {
+			name = op.Name
+		}
+	}
+
+	ctx, span := Start(ctx, fmt.Sprintf("%s %s", kind, name),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.name", name),
+			attribute.String("graphql.operation.type", kind),
+		),
+	)
+	handler := next(ctx)
+
+	// A subscription keeps its span open until the stream ends, any other
+	// operation ends it with its only response.
+	return func(ctx context.Context) *graphql.Response {
+		resp := handler(ctx)
+		if resp == nil {
+			span.End()
+			return nil
+		}
+
+		if len(resp.Errors) > 0 {
+			End(span, errors.New(resp.Errors.Error()))
+		} else if kind != string(ast.Subscription) {
+			span.End()
+		}
+		return resp
+	}
+}
+
+func (e Extension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := Start(ctx, fc.Object+"."+fc.Field.Name, trace.WithAttributes(
+		attribute.String("graphql.field.path", fc.Path().String()),
+	))
+	res, err := next(ctx)
+	End(span, err)
+	return res, err
+}
@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_parent_same_post_fkey;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_id_post_id_key;
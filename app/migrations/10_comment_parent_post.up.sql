@@ -0,0 +1,9 @@
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_parent_same_post_fkey;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_id_post_id_key;
+
+ALTER TABLE comments ADD CONSTRAINT comments_id_post_id_key UNIQUE (id, post_id);
+
+-- NOT VALID skips rows written before the check existed; find them with
+-- `app repair-comments` and run VALIDATE CONSTRAINT once they are fixed.
+ALTER TABLE comments ADD CONSTRAINT comments_parent_same_post_fkey
+    FOREIGN KEY (parent_id, post_id) REFERENCES comments (id, post_id) NOT VALID;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comments_user_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
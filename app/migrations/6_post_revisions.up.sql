@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post ON post_revisions USING btree(post_id, created_at DESC, id DESC);
@@ -0,0 +1,10 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS comment_history (
+    id UUID PRIMARY KEY,
+    comment_id UUID NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_history_comment ON comment_history USING btree(comment_id, created_at);
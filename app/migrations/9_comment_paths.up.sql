@@ -0,0 +1,17 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS path UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INT NOT NULL DEFAULT 0;
+
+WITH RECURSIVE tree AS (
+    SELECT id, ARRAY[]::UUID[] AS path, 0 AS depth
+    FROM comments
+    WHERE parent_id IS NULL
+    UNION ALL
+    SELECT c.id, t.path || c.parent_id, t.depth + 1
+    FROM comments c
+    JOIN tree t ON c.parent_id = t.id
+)
+UPDATE comments SET path = tree.path, depth = tree.depth
+FROM tree
+WHERE comments.id = tree.id;
+
+CREATE INDEX IF NOT EXISTS idx_comments_path ON comments USING gin(path);